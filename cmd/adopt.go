@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bufio"
+	"debug/buildinfo"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"golang.org/x/mod/semver"
+)
+
+var adoptOptions struct {
+	all bool
+}
+
+var adoptCmd = &cobra.Command{
+	Use:     "adopt",
+	Short:   "Track binaries already installed in the go bin dir",
+	Long:    `Track binaries already installed in the go bin dir, reading the module and version from their build info`,
+	Example: fmt.Sprintf("  %s adopt --all", binaryName),
+	Args:    cobra.NoArgs,
+	RunE:    runAdopt,
+}
+
+func init() {
+	rootCmd.AddCommand(adoptCmd)
+
+	adoptCmd.Flags().BoolVarP(
+		&adoptOptions.all,
+		"all",
+		"a",
+		false,
+		"track all binaries found without asking",
+	)
+}
+
+// binDirExecutables returns the names of the executable files in the dir,
+// ignoring hidden files, as those are temporary files.
+func binDirExecutables(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dir %s: %w", dir, err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat %s: %w", entry.Name(), err)
+		}
+
+		if info.Mode().Perm()&0o111 != 0 {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func runAdopt(cmd *cobra.Command, _ []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	names, err := binDirExecutables(path)
+	if err != nil {
+		return err
+	}
+
+	tracked := trackedBinaries(db.GetAllItems())
+	input := bufio.NewReader(cmd.InOrStdin())
+	adopted := 0
+	for _, name := range names {
+		if tracked[name] {
+			continue
+		}
+
+		info, err := buildinfo.ReadFile(filepath.Join(path, name))
+		if err != nil {
+			slog.Info("Skipping binary without go build info", "name", name, "error", err)
+			continue
+		}
+
+		if !semver.IsValid(info.Main.Version) {
+			fmt.Println(rootOptions.colorScheme.Text(
+				"Skipping " + name + ": version " + info.Main.Version + " can not be installed with go install",
+			))
+			continue
+		}
+
+		item := pkg.FromBuildInfo(name, info)
+		item.BinDir = path
+		if !adoptOptions.all {
+			ok, err := confirm(input, "Track "+item.Name+" ("+item.URIWithVersion()+")?")
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				continue
+			}
+		}
+
+		// the binary is trusted as found when adopted
+		err = item.RecordChecksums(path)
+		if err != nil {
+			return err
+		}
+
+		err = db.SaveItem(item.ID(), item)
+		if err != nil {
+			return fmt.Errorf("failed to save adopted package %s: %v", item.Name, err)
+		}
+
+		adopted++
+		fmt.Println(rootOptions.colorScheme.Header("Adopted package: " + item.Name))
+	}
+
+	if adopted == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("No binaries adopted."))
+	}
+
+	return nil
+}
+
+func confirm(input *bufio.Reader, question string) (bool, error) {
+	fmt.Print(rootOptions.colorScheme.Text(question + " [y/N] "))
+	answer, err := input.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, fmt.Errorf("failed to read answer: %w", err)
+	}
+
+	return slices.Contains([]string{"y", "yes"}, strings.ToLower(strings.TrimSpace(answer))), nil
+}
+
+// trackedBinaries returns the names of the binaries installed by the packages.
+func trackedBinaries(items map[string]pkg.Package) map[string]bool {
+	tracked := make(map[string]bool, len(items))
+	for _, item := range items {
+		for _, name := range item.BinaryNames() {
+			tracked[name] = true
+		}
+	}
+
+	return tracked
+}
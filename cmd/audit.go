@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"bufio"
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/osv"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/storage"
+	"golang.org/x/mod/semver"
+)
+
+var auditOptions struct {
+	dbPath       string
+	outputFormat string
+	update       bool
+	yes          bool
+}
+
+var auditCmd = &cobra.Command{
+	Use:   "audit [name...]",
+	Short: "Report known vulnerabilities in installed packages",
+	Long: `Report known vulnerabilities in installed packages, matching the module, dependency and go versions
+in the build info of the binaries against an OSV vulnerability database, exiting with failure if any is affected`,
+	Example: fmt.Sprintf(`  %[1]s audit --db ~/vulndb.zip
+  %[1]s audit --db ~/vulndb --update`, binaryName),
+	ValidArgsFunction: installedPackagesCompletion,
+	SilenceUsage:      true,
+	RunE:              runAudit,
+}
+
+type auditFinding struct {
+	Name string `json:"name"`
+	osv.Finding
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().StringVar(
+		&auditOptions.dbPath,
+		"db",
+		"",
+		"dir or zip file with the OSV vulnerability entries, like https://vuln.go.dev/vulndb.zip",
+	)
+	cobra.CheckErr(auditCmd.MarkFlagRequired("db"))
+
+	auditCmd.Flags().StringVarP(
+		&auditOptions.outputFormat,
+		"output",
+		"o",
+		"text",
+		"Output format: "+strings.Join(availableOutputs, ", "),
+	)
+	cobra.CheckErr(auditCmd.RegisterFlagCompletionFunc(
+		"output",
+		cobra.FixedCompletions(availableOutputs, cobra.ShellCompDirectiveDefault),
+	))
+
+	auditCmd.Flags().BoolVar(
+		&auditOptions.update,
+		"update",
+		false,
+		"ask to update each affected package",
+	)
+
+	auditCmd.Flags().BoolVarP(
+		&auditOptions.yes,
+		"yes",
+		"y",
+		false,
+		"update the affected packages without asking, with --update",
+	)
+}
+
+func runAudit(cmd *cobra.Command, args []string) error {
+	vulndb, err := osv.Load(auditOptions.dbPath)
+	if err != nil {
+		return err
+	}
+
+	db := newStorage()
+	err = db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	var items []pkg.Package
+	for _, name := range args {
+		item, found := db.GetItem(name)
+		if !found {
+			return fmt.Errorf("package %s not found in storage", name)
+		}
+
+		items = append(items, item)
+	}
+
+	if len(args) == 0 {
+		items = slices.Collect(maps.Values(db.GetAllItems()))
+	}
+
+	slices.SortFunc(items, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	findings := []auditFinding{}
+	var affected []pkg.Package
+	for _, item := range items {
+		found := auditPackage(vulndb, item, path)
+		for _, finding := range found {
+			findings = append(findings, auditFinding{Name: item.Name, Finding: finding})
+		}
+
+		if len(found) > 0 {
+			affected = append(affected, item)
+		}
+	}
+
+	switch auditOptions.outputFormat {
+	case "json":
+		err = printAuditAsJSON(findings)
+	case "text":
+		err = printAuditAsText(findings)
+	}
+	if err != nil {
+		return err
+	}
+
+	if auditOptions.update && len(affected) > 0 {
+		err = updateAffected(cmd, db, affected, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(affected) > 0 {
+		return fmt.Errorf("%d packages affected by %d vulnerabilities", len(affected), len(findings))
+	}
+
+	return nil
+}
+
+// auditPackage returns the vulnerabilities of the modules built into the
+// binaries of the package, and of the go standard library they were built with.
+func auditPackage(vulndb *osv.Database, item pkg.Package, path string) []osv.Finding {
+	var findings []osv.Finding
+	seen := map[string]bool{}
+	for _, name := range item.BinaryNames() {
+		info, err := buildinfo.ReadFile(filepath.Join(item.InstallDir(path), name))
+		if err != nil {
+			slog.Warn("skipping binary without build info", "package", item.Name, "binary", name, "error", err)
+			continue
+		}
+
+		for _, module := range buildModules(info) {
+			for _, finding := range vulndb.Query(module.Path, module.Version) {
+				key := finding.ID + " " + finding.Module + "@" + finding.Version
+				if !seen[key] {
+					seen[key] = true
+					findings = append(findings, finding)
+				}
+			}
+		}
+	}
+
+	return findings
+}
+
+// buildModules returns the main module, the dependencies, following their
+// replacements, and the standard library of the build info.
+func buildModules(info *debug.BuildInfo) []debug.Module {
+	modules := []debug.Module{info.Main}
+	for _, dep := range info.Deps {
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+
+		modules = append(modules, *dep)
+	}
+
+	stdlib := goSemver(info.GoVersion)
+	if stdlib != "" {
+		modules = append(modules, debug.Module{Path: osv.Stdlib, Version: stdlib})
+	}
+
+	return modules
+}
+
+// goSemver converts a go version like go1.22.1 to the semver used by the
+// vulnerability database for the standard library.
+func goSemver(goVersion string) string {
+	goVersion, _, _ = strings.Cut(goVersion, " ")
+	version := "v" + strings.TrimPrefix(goVersion, "go")
+	for _, pre := range []string{"rc", "beta"} {
+		version = strings.Replace(version, pre, "-"+pre+".", 1)
+	}
+
+	if !semver.IsValid(version) {
+		return ""
+	}
+
+	return semver.Canonical(version)
+}
+
+func updateAffected(cmd *cobra.Command, db *storage.Provider[pkg.Package], affected []pkg.Package, path string) error {
+	input := bufio.NewReader(cmd.InOrStdin())
+	var results []jobResult
+	for _, item := range affected {
+		if item.Pinned {
+			results = append(results, jobResult{name: item.Name, skipped: "pinned at " + item.Version})
+			continue
+		}
+
+		if !auditOptions.yes {
+			ok, err := confirm(input, "Update "+item.Name+" ("+item.InstalledVersion+")?")
+			if err != nil {
+				return err
+			}
+
+			if !ok {
+				results = append(results, jobResult{name: item.Name, skipped: "not confirmed"})
+				continue
+			}
+		}
+
+		output, err := updatePackage(db, item, path)
+		results = append(results, jobResult{name: item.Name, output: output, err: err})
+	}
+
+	return printJobResults("updated", results)
+}
+
+func printAuditAsJSON(findings []auditFinding) error {
+	bytes, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode vulnerabilities to json: %w", err)
+	}
+
+	fmt.Println(string(bytes))
+
+	return nil
+}
+
+func printAuditAsText(findings []auditFinding) error {
+	if len(findings) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("No known vulnerabilities found."))
+		return nil
+	}
+
+	rows := [][]string{{"NAME", "MODULE", "VERSION", "VULNERABILITY", "FIXED"}}
+	for _, finding := range findings {
+		fixed := finding.Fixed
+		if fixed == "" {
+			fixed = "none"
+		}
+
+		id := finding.ID
+		if len(finding.Aliases) > 0 {
+			id += " (" + strings.Join(finding.Aliases, ", ") + ")"
+		}
+
+		rows = append(rows, []string{finding.Name, finding.Module, finding.Version, id, fixed})
+	}
+
+	return printTable(rows)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+var checkOptions struct {
+	timeout time.Duration
+	jobs    int
+}
+
+var checkCmd = &cobra.Command{
+	Use:   "check [name...]",
+	Short: "Run the checks of installed packages",
+	Long: `Run the checks of installed packages, set with install --check, exiting with failure
+if any check fails (default all packages)`,
+	Example:           fmt.Sprintf("  %s check %s --timeout 5s", binaryName, binaryName),
+	ValidArgsFunction: installedPackagesCompletion,
+	SilenceUsage:      true,
+	RunE:              runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().DurationVar(
+		&checkOptions.timeout,
+		"timeout",
+		pkg.CheckTimeout,
+		"time a check may run before it fails",
+	)
+
+	addJobsFlag(checkCmd, &checkOptions.jobs)
+}
+
+func runCheck(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	var all []pkg.Package
+	for _, name := range args {
+		item, found := db.GetItem(name)
+		if !found {
+			return fmt.Errorf("package %s not found in storage", name)
+		}
+
+		all = append(all, item)
+	}
+
+	if len(args) == 0 {
+		all = slices.Collect(maps.Values(db.GetAllItems()))
+	}
+
+	var items []pkg.Package
+	var skipped []jobResult
+	for _, item := range all {
+		if !item.HasCheck() {
+			skipped = append(skipped, jobResult{name: item.Name, skipped: "no check set, use install --check"})
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	slices.SortFunc(items, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	results, err := runJobs(checkOptions.jobs, false, items, func(item pkg.Package) jobResult {
+		return jobResult{name: item.Name, err: runPackageCheck(item, path)}
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(skipped, func(a, b jobResult) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return printJobResults("checked", append(results, skipped...))
+}
+
+func runPackageCheck(item pkg.Package, path string) error {
+	for _, name := range item.BinaryNames() {
+		err := item.RunCheck(filepath.Join(item.InstallDir(path), name), checkOptions.timeout)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"go/version"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/storage"
+	"golang.org/x/mod/semver"
+)
+
+const (
+	problemMissing   = "Tracked binaries missing:"
+	problemUntracked = "Untracked binaries:"
+	problemMismatch  = "Binaries not matching storage:"
+	problemToolchain = "Binaries built with an older go toolchain:"
+)
+
+var problemKinds = []string{problemMissing, problemUntracked, problemMismatch, problemToolchain}
+
+var doctorOptions struct {
+	fix bool
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that tracked packages match the binaries installed",
+	Long: `Check that tracked packages match the binaries installed, reporting missing, untracked,
+mismatched and built with an older go toolchain binaries`,
+	Example:      fmt.Sprintf("  %s doctor --fix", binaryName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runDoctor,
+}
+
+type problem struct {
+	kind   string
+	name   string
+	detail string
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVar(
+		&doctorOptions.fix,
+		"fix",
+		false,
+		"untrack missing binaries and reinstall mismatched or outdated toolchain ones",
+	)
+}
+
+// goToolchain returns the version of the current go toolchain, like go1.25.1.
+func goToolchain() (string, error) {
+	toolchain, err := goEnv("GOVERSION")
+	if err != nil {
+		return "", err
+	}
+
+	if !version.IsValid(toolchain) {
+		return "", fmt.Errorf("invalid go toolchain version %s", toolchain)
+	}
+
+	return toolchain, nil
+}
+
+// olderToolchain reports whether the binary was built with a go toolchain
+// older than the current one.
+func olderToolchain(info *buildinfo.BuildInfo, toolchain string) bool {
+	return version.Compare(info.GoVersion, toolchain) < 0
+}
+
+// recordedVersion returns the version to reinstall the package without
+// changing it, the installed version when known.
+func recordedVersion(item pkg.Package) (string, error) {
+	if item.FromSource() {
+		return item.Version, nil
+	}
+
+	if semver.IsValid(item.InstalledVersion) {
+		return item.InstalledVersion, nil
+	}
+
+	return targetVersion(item)
+}
+
+func reinstallPackage(db *storage.Provider[pkg.Package], item pkg.Package, path string) (string, error) {
+	version, err := recordedVersion(item)
+	if err != nil {
+		return "", err
+	}
+
+	slog.Info("Reinstalling package", "package", item.URI, "version", version)
+	path = item.InstallDir(path)
+	output, err := goInstall(&item, version, path, claimBinaries(db, item, path, false))
+	if err != nil {
+		return "", fmt.Errorf("failed to install package %s@%s: %v", item.URI, version, err)
+	}
+
+	readBuildInfo(&item, path)
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return output, fmt.Errorf("failed to save package %s: %v", item.Name, err)
+	}
+
+	return output, nil
+}
+
+func runDoctor(_ *cobra.Command, _ []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	toolchain, err := goToolchain()
+	if err != nil {
+		return err
+	}
+
+	names, err := binDirExecutables(path)
+	if err != nil {
+		return err
+	}
+
+	items := db.GetAllItems()
+	tracked := trackedBinaries(items)
+	var problems []problem
+	for _, name := range names {
+		if tracked[name] {
+			continue
+		}
+
+		// only go binaries can be tracked
+		_, err := buildinfo.ReadFile(filepath.Join(path, name))
+		if err == nil {
+			problems = append(problems, problem{kind: problemUntracked, name: name, detail: "use adopt to track it"})
+		}
+	}
+
+	for _, item := range items {
+		p, err := checkPackage(item, item.InstallDir(path), toolchain)
+		if err != nil {
+			return err
+		}
+
+		if p != nil {
+			problems = append(problems, *p)
+		}
+	}
+
+	if len(problems) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("No problems found."))
+		return nil
+	}
+
+	slices.SortFunc(problems, func(a, b problem) int {
+		return strings.Compare(a.name, b.name)
+	})
+	printProblems(problems)
+
+	if !doctorOptions.fix {
+		return fmt.Errorf("%d problems found", len(problems))
+	}
+
+	return fixProblems(db, items, problems, path)
+}
+
+// checkPackage returns the first problem found in the binaries of the package.
+// Problems of a group name the binary, as they are fixed for the whole group.
+func checkPackage(item pkg.Package, dir, toolchain string) (*problem, error) {
+	names := item.BinaryNames()
+	if len(names) == 0 {
+		return &problem{kind: problemMissing, name: item.Name, detail: "no binaries recorded"}, nil
+	}
+
+	for _, name := range names {
+		prefix := ""
+		if item.IsGroup() {
+			prefix = name + " "
+		}
+
+		exists, err := fileExists(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to check if file exists: %v", err)
+		}
+
+		if !exists {
+			// a group is only partially removed when a binary is missing, so it is reinstalled
+			kind := problemMissing
+			if item.IsGroup() {
+				kind = problemMismatch
+			}
+
+			return &problem{kind: kind, name: item.Name, detail: prefix + "not found in " + dir}, nil
+		}
+
+		info, err := buildinfo.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return &problem{kind: problemMismatch, name: item.Name, detail: prefix + err.Error()}, nil
+		}
+
+		switch {
+		case !item.MatchesPath(info.Path):
+			return &problem{
+				kind:   problemMismatch,
+				name:   item.Name,
+				detail: prefix + "built from " + info.Path + ", tracked as " + item.URI,
+			}, nil
+		case item.InstalledVersion != "" && info.Main.Version != pkg.DevelVersion &&
+			info.Main.Version != item.InstalledVersion:
+			return &problem{
+				kind:   problemMismatch,
+				name:   item.Name,
+				detail: prefix + "version " + info.Main.Version + ", tracked as " + item.InstalledVersion,
+			}, nil
+		case olderToolchain(info, toolchain):
+			return &problem{
+				kind:   problemToolchain,
+				name:   item.Name,
+				detail: prefix + "built with " + info.GoVersion + ", current is " + toolchain,
+			}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func printProblems(problems []problem) {
+	for _, kind := range problemKinds {
+		header := false
+		for _, p := range problems {
+			if p.kind != kind {
+				continue
+			}
+
+			if !header {
+				fmt.Println(rootOptions.colorScheme.Header(kind))
+				header = true
+			}
+			fmt.Println(rootOptions.colorScheme.Text("  " + p.name + ": " + p.detail))
+		}
+	}
+}
+
+func fixProblems(
+	db *storage.Provider[pkg.Package],
+	items map[string]pkg.Package,
+	problems []problem,
+	path string,
+) error {
+	failures := 0
+	for _, p := range problems {
+		var err error
+		switch p.kind {
+		case problemMissing:
+			err = db.DeleteItem(p.name)
+			if err == nil {
+				fmt.Println(rootOptions.colorScheme.Text("Untracked missing package: " + p.name))
+			}
+		case problemMismatch, problemToolchain:
+			var output string
+			output, err = reinstallPackage(db, items[p.name], path)
+			if err == nil {
+				printOutput(output)
+				fmt.Println(rootOptions.colorScheme.Text("Reinstalled package: " + p.name))
+			}
+		}
+
+		if err != nil {
+			failures++
+			fmt.Println(rootOptions.colorScheme.Err("Failed to fix " + p.name + ": " + err.Error()))
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("failed to fix %d problems", failures)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+func printDryRun(action string) {
+	fmt.Println(rootOptions.colorScheme.Text("[dry-run] " + action))
+}
+
+// goInstall installs the package into the bin dir, recording it in the package,
+// only printing the go install command in dry run mode.
+func goInstall(pack *pkg.Package, version, binDir string, claim pkg.ClaimFunc) (string, error) {
+	pack.BinDir = binDir
+	if rootOptions.dryRun {
+		cmd := pack.InstallCommand(version)
+		action := "run " + strings.Join(append(pack.CommandEnv(), cmd.String()), " ")
+		switch pack.SourceType() {
+		case pkg.SourceGit:
+			printDryRun("checkout " + pack.Repository + " at " + version)
+			action += " in " + pack.Dir + " of the checkout"
+		case pkg.SourceLocal:
+			action += " in " + pack.Dir
+		}
+
+		if pack.HasCheck() {
+			action += ", checking it with " + strings.Join(pack.Check, " ")
+		}
+
+		if !pack.IsGroup() {
+			action += ", installing " + filepath.Join(binDir, pack.Name)
+		}
+
+		printDryRun(action)
+		return "", nil
+	}
+
+	return pack.Install(version, claim)
+}
+
+// linkFile points the link to the target, replacing the link atomically.
+func linkFile(target, link string) error {
+	if rootOptions.dryRun {
+		printDryRun("link " + link + " to " + target)
+		return nil
+	}
+
+	tmp := link + ".gomanager"
+	_ = os.Remove(tmp)
+	err := os.Symlink(target, tmp)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, link)
+}
+
+func removeFile(path string) error {
+	if rootOptions.dryRun {
+		printDryRun("remove " + path)
+		return nil
+	}
+
+	return os.Remove(path)
+}
@@ -6,8 +6,6 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
-	"github.com/tcondeixa/gomanager/internal/pkg"
-	"github.com/tcondeixa/gomanager/internal/storage"
 )
 
 const defaultExportFileName = binaryName + ".json"
@@ -40,7 +38,7 @@ func init() {
 }
 
 func runExport(_ *cobra.Command, _ []string) error {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return fmt.Errorf("failed to load storage: %w", err)
@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tcondeixa/gomanager/internal/pkg"
@@ -13,15 +16,18 @@ import (
 
 var importOptions struct {
 	filePath string
+	jobs     int
+	failFast bool
 }
 
 var importCmd = &cobra.Command{
-	Use:     "import",
-	Short:   "import installed packages from file",
-	Long:    `import installed packages from file`,
-	Example: fmt.Sprintf("  %s import -f /tmp/%s", binaryName, defaultExportFileName),
-	Args:    cobra.NoArgs,
-	RunE:    runimport,
+	Use:          "import",
+	Short:        "import installed packages from file",
+	Long:         `import installed packages from file`,
+	Example:      fmt.Sprintf("  %s import -f /tmp/%s", binaryName, defaultExportFileName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runimport,
 }
 
 func init() {
@@ -36,32 +42,70 @@ func init() {
 		filepath.Join(home, defaultExportFileName),
 		"filepath to import list of installed packages",
 	)
+
+	addJobsFlag(importCmd, &importOptions.jobs)
+	addFailFastFlag(importCmd, &importOptions.failFast)
 }
 
 func runimport(_ *cobra.Command, _ []string) error {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Import(importOptions.filePath)
 	if err != nil {
 		return fmt.Errorf("failed to import storage: %w", err)
 	}
 
-	items := db.GetAllItems()
-	for _, item := range items {
-		slog.Info("Install package", "package", item.URI, "current_version", item.Version)
-		output, err := item.Install()
-		if err != nil {
-			return fmt.Errorf("failed to install package %s: %v", item, err)
-		}
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	items := slices.Collect(maps.Values(db.GetAllItems()))
+	slices.SortFunc(items, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	results, err := runJobs(importOptions.jobs, importOptions.failFast, items, func(item pkg.Package) jobResult {
+		output, err := importPackage(db, item, path)
+		return jobResult{name: item.Name, output: output, err: err}
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJobResults("installed", results)
+}
 
-		fmt.Println(rootOptions.colorScheme.Text(output))
+func importPackage(db *storage.Provider[pkg.Package], item pkg.Package, path string) (string, error) {
+	slog.Info("Install package", "package", item.URI, "current_version", item.Version)
+	version, err := targetVersion(item)
+	if err != nil {
+		return "", err
+	}
 
-		err = db.SaveItem(item.ID(), item)
+	// the recorded dir may not exist when importing from another machine
+	if item.BinDir != "" {
+		exists, err := fileExists(item.BinDir)
 		if err != nil {
-			return fmt.Errorf("failed to save installed package %s: %v", item, err)
+			return "", fmt.Errorf("failed to check if dir exists: %v", err)
+		}
+
+		if !exists {
+			slog.Warn("Recorded bin dir not found, using go bin dir", "package", item.Name, "path", item.BinDir)
+			item.BinDir = ""
 		}
+	}
 
-		fmt.Println("Installed package: ", item.Name)
+	path = item.InstallDir(path)
+	output, err := goInstall(&item, version, path, claimBinaries(db, item, path, false))
+	if err != nil {
+		return "", fmt.Errorf("failed to install package %s: %v", item.URIWithVersion(), err)
+	}
+
+	readBuildInfo(&item, path)
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return output, fmt.Errorf("failed to save installed package %s: %v", item.Name, err)
 	}
 
-	return nil
+	return output, nil
 }
@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tcondeixa/gomanager/internal/pkg"
@@ -12,15 +15,30 @@ import (
 )
 
 var installOptions struct {
-	name string
+	name       string
+	binDir     string
+	tags       []string
+	ldflags    string
+	buildFlags []string
+	env        []string
+	git        string
+	ref        string
+	versioned  bool
+	force      bool
+	check      string
+	exitCode   int
+	jobs       int
 }
 
 var installCmd = &cobra.Command{
-	Use:     "install",
-	Short:   "Install packages",
-	Long:    `Install packages`,
-	Example: fmt.Sprintf("  %s install github.com/tcondeixa/gomanager@latest", binaryName),
-	RunE:    runInstall,
+	Use:   "install <package@version | dir>...",
+	Short: "Install packages",
+	Long:  `Install packages from the module proxy, a local dir or a git repository`,
+	Example: fmt.Sprintf(`  %[1]s install github.com/tcondeixa/gomanager@latest
+  %[1]s install ./gomanager
+  %[1]s install --git https://github.com/tcondeixa/gomanager --ref main .`, binaryName),
+	SilenceUsage: true,
+	RunE:         runInstall,
 }
 
 func init() {
@@ -33,6 +51,87 @@ func init() {
 		"",
 		"Force name of the binary (default to go install name)",
 	)
+
+	installCmd.Flags().StringVar(
+		&installOptions.binDir,
+		"bin-dir",
+		"",
+		"dir to install the binaries into, recorded for updates (default to the go bin dir)",
+	)
+	cobra.CheckErr(installCmd.MarkFlagDirname("bin-dir"))
+
+	installCmd.Flags().StringSliceVar(
+		&installOptions.tags,
+		"tags",
+		nil,
+		"build tags, recorded to rebuild the package the same way on update",
+	)
+
+	installCmd.Flags().StringVar(
+		&installOptions.ldflags,
+		"ldflags",
+		"",
+		"go install -ldflags value, recorded to rebuild the package the same way on update",
+	)
+
+	installCmd.Flags().StringArrayVar(
+		&installOptions.buildFlags,
+		"build-flag",
+		nil,
+		"extra go install flag, like -trimpath, recorded to rebuild the package the same way on update",
+	)
+
+	installCmd.Flags().StringArrayVar(
+		&installOptions.env,
+		"env",
+		nil,
+		"KEY=VALUE environment variable for go install, like CGO_ENABLED=0 or GOFLAGS, recorded for updates",
+	)
+
+	installCmd.Flags().StringVar(
+		&installOptions.git,
+		"git",
+		"",
+		"git repository to build the packages from, given as dirs relative to its root (default to the root)",
+	)
+
+	installCmd.Flags().StringVar(
+		&installOptions.ref,
+		"ref",
+		"",
+		"branch, tag or commit of the git repository, rebuilt on update (default to "+pkg.DefaultRef+")",
+	)
+
+	installCmd.Flags().BoolVar(
+		&installOptions.versioned,
+		"versioned",
+		false,
+		"install under a name with the major version, like tool-v1, to keep several versions (see use)",
+	)
+
+	installCmd.Flags().StringVar(
+		&installOptions.check,
+		"check",
+		"",
+		"arguments to run the built binary with before replacing the installed one, like \"--version\"",
+	)
+
+	installCmd.Flags().IntVar(
+		&installOptions.exitCode,
+		"check-exit-code",
+		0,
+		"exit code expected from the check",
+	)
+
+	installCmd.Flags().BoolVarP(
+		&installOptions.force,
+		"force",
+		"f",
+		false,
+		"replace a binary with the custom name that belongs to another package or is not tracked",
+	)
+
+	addJobsFlag(installCmd, &installOptions.jobs)
 }
 
 func runInstall(_ *cobra.Command, args []string) error {
@@ -40,7 +139,11 @@ func runInstall(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot use --name when installing multiple packages")
 	}
 
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	if installOptions.versioned && installOptions.name != "" {
+		return fmt.Errorf("cannot use --name with --versioned")
+	}
+
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return err
@@ -51,65 +154,293 @@ func runInstall(_ *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to determine go bin path: %v", err)
 	}
 
-	// install and save to storage
+	if installOptions.binDir != "" {
+		path, err = filepath.Abs(installOptions.binDir)
+		if err != nil {
+			return fmt.Errorf("failed to determine absolute path of %s: %v", installOptions.binDir, err)
+		}
+	}
+
+	err = validateBuildOptions()
+	if err != nil {
+		return err
+	}
+
+	if installOptions.ref != "" && installOptions.git == "" {
+		return fmt.Errorf("cannot use --ref without --git")
+	}
+
+	if installOptions.git != "" && len(args) == 0 {
+		args = []string{"."}
+	}
+
+	packs := make([]pkg.Package, 0, len(args))
 	for _, item := range args {
-		pack, err := pkg.New(item)
+		pack, err := newPackage(item)
 		if err != nil {
 			return fmt.Errorf("failed to create package from %s: %v", item, err)
 		}
 
-		if installOptions.name != "" {
-			oldPath := filepath.Join(path, pack.Name)
-			exists, err := fileExists(oldPath)
-			if err != nil {
-				return fmt.Errorf("failed to check if file exists: %v", err)
-			}
+		pack.Tags = installOptions.tags
+		pack.BuildFlags = installOptions.buildFlags
+		if installOptions.ldflags != "" {
+			pack.BuildFlags = append(slices.Clone(pack.BuildFlags), "-ldflags="+installOptions.ldflags)
+		}
+		pack.Env = installOptions.env
+		pack.Check = strings.Fields(installOptions.check)
+		pack.CheckExitCode = installOptions.exitCode
 
-			if exists {
-				tmpPath := oldPath + ".gomanager"
-				err = os.Rename(oldPath, tmpPath)
-				if err != nil {
-					return fmt.Errorf("failed to rename existing binary: %v", err)
-				}
-
-				defer func() {
-					err = os.Rename(oldPath+".gomanager", oldPath)
-					if err != nil {
-						slog.Error("failed to restore original binary", "error", err)
-					}
-				}()
-			}
+		packs = append(packs, *pack)
+	}
+
+	results, err := runJobs(installOptions.jobs, true, packs, func(pack pkg.Package) jobResult {
+		if installOptions.versioned {
+			output, err := installVariant(db, &pack, path)
+			return jobResult{name: pack.Name, output: output, err: err}
 		}
 
-		output, err := pack.Install()
-		if err != nil {
-			return fmt.Errorf("failed to install package %s: %v", item, err)
+		output, err := installPackage(db, &pack, path, installOptions.name, installOptions.force)
+		return jobResult{name: pack.Name, output: output, err: err}
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJobResults("installed", results)
+}
+
+// newPackage creates the package to install from the argument, a module
+// package with a version, a local dir or a dir of the --git repository.
+func newPackage(arg string) (*pkg.Package, error) {
+	switch {
+	case installOptions.git != "":
+		return pkg.NewGit(installOptions.git, installOptions.ref, arg)
+	case isLocalPath(arg):
+		return pkg.NewLocal(arg)
+	default:
+		return pkg.New(arg)
+	}
+}
+
+// isLocalPath reports whether the argument is a dir instead of a package path,
+// which never starts with a dot or a slash.
+func isLocalPath(arg string) bool {
+	return filepath.IsAbs(arg) || arg == "." || arg == ".." ||
+		strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../")
+}
+
+func validateBuildOptions() error {
+	for _, flag := range installOptions.buildFlags {
+		if !strings.HasPrefix(flag, "-") {
+			return fmt.Errorf("invalid build flag %q: must start with -", flag)
+		}
+
+		name, _, _ := strings.Cut(strings.TrimLeft(flag, "-"), "=")
+		if name == "o" || name == "tags" || name == "ldflags" {
+			return fmt.Errorf("invalid build flag %q: use the dedicated option instead", flag)
+		}
+	}
+
+	for _, env := range installOptions.env {
+		key, _, found := strings.Cut(env, "=")
+		if !found || key == "" {
+			return fmt.Errorf("invalid environment variable %q: must be KEY=VALUE", env)
+		}
+
+		if key == "GOBIN" {
+			return fmt.Errorf("invalid environment variable %q: use --bin-dir instead", env)
 		}
+	}
+
+	return nil
+}
+
+// installPackage installs and saves the package to storage, with the custom
+// name when given. go install builds into a staging dir, so the binary is
+// moved into place with its final name, without touching other binaries.
+func installPackage(
+	db *storage.Provider[pkg.Package],
+	pack *pkg.Package,
+	path, name string,
+	force bool,
+) (string, error) {
+	if name == pack.Name {
+		name = ""
+	}
+
+	if name != "" && pack.IsGroup() {
+		return "", fmt.Errorf("cannot use a custom name for %s, as it installs several binaries", pack.URI)
+	}
 
-		fmt.Println(rootOptions.colorScheme.Text(output))
+	path = pack.InstallDir(path)
 
-		if installOptions.name != "" {
-			oldPath := filepath.Join(path, pack.Name)
-			newPath := filepath.Join(path, installOptions.name)
-			err = os.Rename(oldPath, newPath)
+	if name != "" {
+		if !force {
+			err := checkNameFree(db, *pack, path, name)
 			if err != nil {
-				return fmt.Errorf("failed to rename binary to %s: %v", installOptions.name, err)
+				return "", err
 			}
-
-			pack.Name = installOptions.name
 		}
 
-		err = db.SaveItem(pack.ID(), *pack)
+		pack.Name = name
+	} else if !force && !pack.IsGroup() {
+		err := checkNameOwner(db, *pack)
 		if err != nil {
-			return err
+			return "", err
 		}
+	}
+
+	version, err := targetVersion(*pack)
+	if err != nil {
+		return "", err
+	}
 
-		fmt.Println(rootOptions.colorScheme.Header("Installed package: " + pack.Name))
+	output, err := goInstall(pack, version, path, claimBinaries(db, *pack, path, force))
+	if err != nil {
+		return "", fmt.Errorf("failed to install package %s: %v", pack.URIWithVersion(), err)
+	}
+
+	readBuildInfo(pack, path)
+
+	err = db.SaveItem(pack.ID(), *pack)
+	if err != nil {
+		return output, err
+	}
+
+	return output, nil
+}
+
+// checkNameFree returns an error when the name is used by another tracked
+// package, links to a variant or is an untracked file, which the install would
+// replace.
+func checkNameFree(db *storage.Provider[pkg.Package], pack pkg.Package, path, name string) error {
+	for _, item := range db.GetAllItems() {
+		if item.Alias == name {
+			return fmt.Errorf("name %s is a link to a variant, use --force to replace it", name)
+		}
+
+		if !slices.Contains(item.BinaryNames(), name) || item.ID() == pack.ID() {
+			continue
+		}
+
+		if item.ID() == name && item.URI == pack.URI {
+			return nil
+		}
+
+		return fmt.Errorf("name %s is used by package %s (%s), use --force to replace it", name, item.Name, item.URI)
+	}
+
+	exists, err := fileExists(filepath.Join(path, name))
+	if err != nil {
+		return fmt.Errorf("failed to check if file exists: %v", err)
+	}
+
+	if exists {
+		return fmt.Errorf("%s already exists and is not tracked, use --force to replace it", filepath.Join(path, name))
+	}
+
+	return nil
+}
+
+// checkNameOwner returns an error when the binary name of the package is
+// installed by another tracked package or links to a variant. Untracked files
+// are replaced, as go install does.
+func checkNameOwner(db *storage.Provider[pkg.Package], pack pkg.Package) error {
+	owner, found := binaryOwner(db, pack, pack.Name)
+	if found {
+		return fmt.Errorf(
+			"name %s is used by package %s (%s), use --force to replace it",
+			pack.Name,
+			owner.Name,
+			owner.URI,
+		)
+	}
+
+	for _, item := range db.GetAllItems() {
+		if item.Alias == pack.Name {
+			return fmt.Errorf("name %s is a link to a variant, use --force to replace it", pack.Name)
+		}
 	}
 
 	return nil
 }
 
+// claimBinaries checks the binaries a group installs for the first time with
+// the rules of custom names, unless forced.
+func claimBinaries(db *storage.Provider[pkg.Package], pack pkg.Package, path string, force bool) pkg.ClaimFunc {
+	if force {
+		return nil
+	}
+
+	return func(name string) error {
+		return checkNameFree(db, pack, path, name)
+	}
+}
+
+func readBuildInfo(pack *pkg.Package, binDir string) {
+	// the binary is not changed in dry run mode
+	if rootOptions.dryRun {
+		return
+	}
+
+	err := pack.ReadBuildInfo(binDir)
+	if err != nil {
+		slog.Warn("failed to read build info", "package", pack.Name, "error", err)
+		return
+	}
+
+	slog.Info(
+		"Resolved installed version",
+		"package", pack.Name,
+		"module", pack.Module,
+		"version", pack.InstalledVersion,
+	)
+}
+
+// copyFile copies the file through a temporary file in the destination
+// directory, so the destination is replaced atomically.
+func copyFile(src, dst string) error {
+	if rootOptions.dryRun {
+		printDryRun("copy " + src + " to " + dst)
+		return nil
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), "."+filepath.Base(dst)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, in)
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
+
 func fileExists(path string) (bool, error) {
 	_, err := os.Stat(path)
 	if err == nil {
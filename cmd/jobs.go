@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+type jobResult struct {
+	name   string
+	output string
+	// skipped holds the reason when the package was not processed.
+	skipped string
+	err     error
+}
+
+func addJobsFlag(cmd *cobra.Command, jobs *int) {
+	cmd.Flags().IntVarP(
+		jobs,
+		"jobs",
+		"j",
+		1,
+		"number of packages installed concurrently",
+	)
+}
+
+func addFailFastFlag(cmd *cobra.Command, failFast *bool) {
+	cmd.Flags().BoolVar(
+		failFast,
+		"fail-fast",
+		false,
+		"stop processing packages after the first failure",
+	)
+}
+
+// runJobs runs fn for every item with at most jobs running concurrently.
+// With failFast no more jobs are started after the first failure and the
+// remaining items are reported as skipped.
+// The results are returned in the same order as the items.
+func runJobs(
+	jobs int,
+	failFast bool,
+	items []pkg.Package,
+	fn func(pkg.Package) jobResult,
+) ([]jobResult, error) {
+	if jobs < 1 {
+		return nil, fmt.Errorf("invalid number of jobs %d, must be at least 1", jobs)
+	}
+
+	results := make([]jobResult, len(items))
+	queue := make(chan int)
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for range min(jobs, len(items)) {
+		wg.Go(func() {
+			for i := range queue {
+				if failFast && failed.Load() {
+					results[i] = jobResult{name: items[i].Name, skipped: "stopped after a previous failure"}
+					continue
+				}
+
+				results[i] = fn(items[i])
+				if results[i].err != nil {
+					failed.Store(true)
+				}
+			}
+		})
+	}
+
+	for i := range items {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+
+	return results, nil
+}
+
+// printOutput prints the output of go install, usually empty.
+func printOutput(output string) {
+	output = strings.TrimSpace(output)
+	if output != "" {
+		fmt.Println(rootOptions.colorScheme.Text(output))
+	}
+}
+
+func printJobResults(action string, results []jobResult) error {
+	if len(results) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("No packages to process."))
+		return nil
+	}
+
+	var succeeded, skipped, failed []jobResult
+	for _, result := range results {
+		printOutput(result.output)
+
+		switch {
+		case result.err != nil:
+			failed = append(failed, result)
+		case result.skipped != "":
+			skipped = append(skipped, result)
+		default:
+			succeeded = append(succeeded, result)
+		}
+	}
+
+	summary := "Summary:"
+	if rootOptions.dryRun {
+		summary = "Summary (dry-run):"
+	}
+
+	fmt.Println(rootOptions.colorScheme.Header(summary))
+	fmt.Println(rootOptions.colorScheme.Header("-------------------"))
+	if len(succeeded) > 0 {
+		fmt.Println(rootOptions.colorScheme.Header(fmt.Sprintf("Succeeded (%d):", len(succeeded))))
+		for _, result := range succeeded {
+			fmt.Println(rootOptions.colorScheme.Text("  " + result.name + ": " + action))
+		}
+	}
+
+	if len(skipped) > 0 {
+		fmt.Println(rootOptions.colorScheme.Header(fmt.Sprintf("Skipped (%d):", len(skipped))))
+		for _, result := range skipped {
+			fmt.Println(rootOptions.colorScheme.Text("  " + result.name + ": " + result.skipped))
+		}
+	}
+
+	if len(failed) > 0 {
+		fmt.Println(rootOptions.colorScheme.Header(fmt.Sprintf("Failed (%d):", len(failed))))
+		for _, result := range failed {
+			fmt.Println(rootOptions.colorScheme.Err("  " + result.name + ": " + strings.TrimSpace(result.err.Error())))
+		}
+
+		return fmt.Errorf("%d packages failed", len(failed))
+	}
+
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 
 	"github.com/spf13/cobra"
 	"github.com/tcondeixa/gomanager/internal/pkg"
-	"github.com/tcondeixa/gomanager/internal/storage"
 )
 
 var availableOutputs = []string{"text", "json"}
@@ -41,7 +40,7 @@ func init() {
 }
 
 func runList(_ *cobra.Command, _ []string) error {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return fmt.Errorf("failed to load storage: %w", err)
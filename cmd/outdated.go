@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"golang.org/x/mod/semver"
+)
+
+var outdatedOptions struct {
+	outputFormat string
+}
+
+var outdatedCmd = &cobra.Command{
+	Use:   "outdated",
+	Short: "List packages with newer versions available",
+	Long: `List packages with newer versions available, exiting with failure if any is outdated.
+Packages are compared with the version update installs, within their constraint, so pinned packages,
+and packages with an exact version, are only outdated when installed at another version`,
+	Example:      fmt.Sprintf("  %s outdated -o json", binaryName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runOutdated,
+}
+
+type outdatedPackage struct {
+	Name      string `json:"name"`
+	Module    string `json:"module"`
+	Installed string `json:"installed"`
+	Available string `json:"available"`
+	// Latest is the newest version of the module, regardless of the constraint.
+	Latest string `json:"latest"`
+}
+
+func init() {
+	rootCmd.AddCommand(outdatedCmd)
+
+	outdatedCmd.Flags().StringVarP(
+		&outdatedOptions.outputFormat,
+		"output",
+		"o",
+		"text",
+		"Output format: "+strings.Join(availableOutputs, ", "),
+	)
+	cobra.CheckErr(outdatedCmd.RegisterFlagCompletionFunc(
+		"output",
+		cobra.FixedCompletions(availableOutputs, cobra.ShellCompDirectiveDefault),
+	))
+}
+
+func runOutdated(_ *cobra.Command, _ []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	client, err := proxyClient()
+	if err != nil {
+		return err
+	}
+
+	outdated := []outdatedPackage{}
+	for _, item := range db.GetAllItems() {
+		if item.FromSource() {
+			slog.Debug("skipping package built from source", "package", item.Name, "source", item.SourceType())
+			continue
+		}
+
+		if item.Pinned {
+			slog.Debug("skipping pinned package", "package", item.Name, "version", item.Version)
+			continue
+		}
+
+		if item.InstalledVersion == "" || item.Module == "" {
+			readBuildInfo(&item, item.InstallDir(path))
+		}
+
+		if !semver.IsValid(item.InstalledVersion) {
+			slog.Warn("skipping package without a known installed version", "package", item.Name)
+			continue
+		}
+
+		module, err := modulePath(client, item)
+		if err != nil {
+			slog.Error("failed to find module", "package", item.Name, "error", err)
+			continue
+		}
+
+		latest, err := client.Latest(module)
+		if err != nil {
+			slog.Error("failed to get latest version", "package", item.Name, "error", err)
+			continue
+		}
+
+		available, err := availableVersion(item, latest.Version)
+		if err != nil {
+			slog.Error("failed to resolve version", "package", item.Name, "error", err)
+			continue
+		}
+
+		if semver.Compare(item.InstalledVersion, available) < 0 {
+			outdated = append(outdated, outdatedPackage{
+				Name:      item.Name,
+				Module:    module,
+				Installed: item.InstalledVersion,
+				Available: available,
+				Latest:    latest.Version,
+			})
+		}
+	}
+
+	slices.SortFunc(outdated, func(a, b outdatedPackage) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	switch outdatedOptions.outputFormat {
+	case "json":
+		err = printOutdatedAsJSON(outdated)
+	case "text":
+		err = printOutdatedAsText(outdated)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(outdated) > 0 {
+		return fmt.Errorf("%d outdated packages found", len(outdated))
+	}
+
+	return nil
+}
+
+// availableVersion returns the version update installs for the package, which
+// keeps exact versions, as they are only updated when forced.
+func availableVersion(item pkg.Package, latest string) (string, error) {
+	if updateSkipReason(item) == "" {
+		item.Version = updateVersion(item)
+	}
+
+	version, err := targetVersion(item)
+	if err != nil {
+		return "", err
+	}
+
+	if !semver.IsValid(version) {
+		return latest, nil
+	}
+
+	return version, nil
+}
+
+func printOutdatedAsJSON(outdated []outdatedPackage) error {
+	bytes, err := json.MarshalIndent(outdated, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode outdated packages to json: %w", err)
+	}
+
+	fmt.Println(string(bytes))
+
+	return nil
+}
+
+func printOutdatedAsText(outdated []outdatedPackage) error {
+	if len(outdated) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("All packages are up to date."))
+		return nil
+	}
+
+	rows := [][]string{{"NAME", "INSTALLED", "AVAILABLE", "LATEST"}}
+	for _, item := range outdated {
+		rows = append(rows, []string{item.Name, item.Installed, item.Available, item.Latest})
+	}
+
+	return printTable(rows)
+}
+
+// printTable aligns the rows before coloring them, as the escape sequences
+// would otherwise count for the width of the columns.
+func printTable(rows [][]string) error {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	err := w.Flush()
+	if err != nil {
+		return fmt.Errorf("failed to format table: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	for i, line := range lines {
+		if i == 0 {
+			fmt.Println(rootOptions.colorScheme.Header(line))
+			continue
+		}
+
+		fmt.Println(rootOptions.colorScheme.Text(line))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/mod/semver"
+)
+
+var pinCmd = &cobra.Command{
+	Use:               "pin <name> [version]",
+	Short:             "Pin package to a version",
+	Long:              `Pin package to a version, installing it if needed (default to the installed version)`,
+	Example:           fmt.Sprintf("  %s pin %s v0.2.1", binaryName, binaryName),
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: firstArgPackageCompletion,
+	RunE:              runPin,
+}
+
+func init() {
+	rootCmd.AddCommand(pinCmd)
+}
+
+func firstArgPackageCompletion(
+	cmd *cobra.Command,
+	args []string,
+	toComplete string,
+) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return []cobra.Completion{}, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return installedPackagesCompletion(cmd, args, toComplete)
+}
+
+func runPin(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	item, found := db.GetItem(args[0])
+	if !found {
+		return fmt.Errorf("package %s not found in storage", args[0])
+	}
+
+	if item.FromSource() {
+		return fmt.Errorf(
+			"package %s is built from %s source, only module packages can be pinned",
+			item.Name,
+			item.Source,
+		)
+	}
+
+	version := item.InstalledVersion
+	if len(args) > 1 {
+		version = args[1]
+	}
+
+	if version == "" {
+		return fmt.Errorf("installed version of %s is unknown, the version to pin is required", item.Name)
+	}
+
+	if !semver.IsValid(version) {
+		return fmt.Errorf("invalid version %s, only exact versions can be pinned", version)
+	}
+
+	if version != item.InstalledVersion {
+		path, err := goBinPath()
+		if err != nil {
+			return fmt.Errorf("failed to determine go bin path: %v", err)
+		}
+
+		path = item.InstallDir(path)
+		output, err := goInstall(&item, version, path, claimBinaries(db, item, path, false))
+		if err != nil {
+			return fmt.Errorf("failed to install package %s@%s: %v", item.URI, version, err)
+		}
+
+		printOutput(output)
+		readBuildInfo(&item, path)
+	}
+
+	item.Pinned = true
+	item.UpdateVersion(version)
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return fmt.Errorf("failed to save pinned package %s: %v", item.Name, err)
+	}
+
+	fmt.Println(rootOptions.colorScheme.Header("Pinned package " + item.Name + " at " + version))
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"debug/buildinfo"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+var rebuildOptions struct {
+	olderToolchain bool
+	jobs           int
+	failFast       bool
+}
+
+var rebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Rebuild packages with the current go toolchain",
+	Long: `Rebuild packages with the current go toolchain, reinstalling them at the installed version,
+for example after a go upgrade`,
+	Example:      fmt.Sprintf("  %s rebuild --older-toolchain", binaryName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runRebuild,
+}
+
+func init() {
+	rootCmd.AddCommand(rebuildCmd)
+
+	rebuildCmd.Flags().BoolVar(
+		&rebuildOptions.olderToolchain,
+		"older-toolchain",
+		false,
+		"only rebuild binaries built with a go toolchain older than the current one",
+	)
+
+	addJobsFlag(rebuildCmd, &rebuildOptions.jobs)
+	addFailFastFlag(rebuildCmd, &rebuildOptions.failFast)
+}
+
+func runRebuild(_ *cobra.Command, _ []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	toolchain, err := goToolchain()
+	if err != nil {
+		return err
+	}
+
+	var items []pkg.Package
+	var skipped []jobResult
+	for _, item := range slices.Collect(maps.Values(db.GetAllItems())) {
+		if rebuildOptions.olderToolchain {
+			goVersion, current := builtWithToolchain(item, path, toolchain)
+			if current {
+				skipped = append(skipped, jobResult{name: item.Name, skipped: "already built with " + goVersion})
+				continue
+			}
+		}
+
+		items = append(items, item)
+	}
+
+	slices.SortFunc(items, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	results, err := runJobs(rebuildOptions.jobs, rebuildOptions.failFast, items, func(item pkg.Package) jobResult {
+		output, err := reinstallPackage(db, item, path)
+		return jobResult{name: item.Name, output: output, err: err}
+	})
+	if err != nil {
+		return err
+	}
+
+	slices.SortFunc(skipped, func(a, b jobResult) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	return printJobResults("rebuilt with "+toolchain, append(results, skipped...))
+}
+
+// builtWithToolchain reports whether all the binaries of the package are built
+// with the toolchain or a newer one, returning the go version of the binaries.
+func builtWithToolchain(item pkg.Package, path, toolchain string) (string, bool) {
+	names := item.BinaryNames()
+	if len(names) == 0 {
+		return "", false
+	}
+
+	goVersion := ""
+	for _, name := range names {
+		info, err := buildinfo.ReadFile(filepath.Join(item.InstallDir(path), name))
+		if err != nil || olderToolchain(info, toolchain) {
+			return "", false
+		}
+
+		goVersion = info.GoVersion
+	}
+
+	return goVersion, true
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/tcondeixa/gomanager/internal/constraint"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/proxy"
+)
+
+var proxyClient = sync.OnceValues(func() (*proxy.Client, error) {
+	goproxy, err := goEnv("GOPROXY")
+	if err != nil {
+		return nil, err
+	}
+
+	return proxy.New(goproxy)
+})
+
+func modulePath(client *proxy.Client, item pkg.Package) (string, error) {
+	if item.Module != "" {
+		return item.Module, nil
+	}
+
+	module, err := client.FindModule(item.URI)
+	if err != nil {
+		return "", fmt.Errorf("failed to find module of %s: %w", item.URI, err)
+	}
+
+	return module, nil
+}
+
+// targetVersion returns the version to give to go install for the package,
+// resolving constraints to the newest version satisfying them.
+func targetVersion(item pkg.Package) (string, error) {
+	if !constraint.IsConstraint(item.Version) {
+		return item.Version, nil
+	}
+
+	c, err := constraint.Parse(item.Version)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := proxyClient()
+	if err != nil {
+		return "", err
+	}
+
+	module, err := modulePath(client, item)
+	if err != nil {
+		return "", err
+	}
+
+	versions, err := client.Versions(module)
+	if err != nil {
+		return "", fmt.Errorf("failed to list versions of %s: %w", module, err)
+	}
+
+	version, found := c.Latest(versions)
+	if !found {
+		return "", fmt.Errorf("no version of %s satisfies %s", module, c)
+	}
+
+	slog.Info("Resolved version constraint", "package", item.Name, "constraint", c, "version", version)
+
+	return version, nil
+}
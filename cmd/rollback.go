@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+const previousDir = "previous"
+
+var rollbackOptions struct {
+	pin bool
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback <name>",
+	Short: "Rollback package to the previously installed version",
+	Long: `Rollback package to the previously installed version,
+restoring the kept binary or reinstalling it`,
+	Example:           fmt.Sprintf("  %s rollback %s --pin", binaryName, binaryName),
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: firstArgPackageCompletion,
+	RunE:              runRollback,
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().BoolVar(
+		&rollbackOptions.pin,
+		"pin",
+		false,
+		"pin the package at the restored version",
+	)
+}
+
+func previousBinaryPath(name, version string) string {
+	return filepath.Join(rootOptions.configDir, previousDir, name+"@"+version)
+}
+
+// backupBinary keeps a copy of the installed binaries before they are replaced
+// by the version, so rollback can restore them without building them again.
+// Older copies are only removed once their version is no longer in the history.
+func backupBinary(item pkg.Package, version, binDir string) error {
+	if item.InstalledVersion == "" || installsSameVersion(item, version) {
+		return nil
+	}
+
+	versions := []string{item.InstalledVersion}
+	for _, release := range item.History {
+		versions = append(versions, release.Version)
+	}
+
+	for _, name := range item.BinaryNames() {
+		err := keepBinary(name, item.InstalledVersion, binDir, versions)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// installsSameVersion reports whether installing the version reinstalls the
+// installed version, resolving latest with the module proxy. The version built
+// from source is only known once built, so it is never the same.
+func installsSameVersion(item pkg.Package, version string) bool {
+	if item.FromSource() {
+		return false
+	}
+
+	if version == "latest" {
+		client, err := proxyClient()
+		if err != nil {
+			return false
+		}
+
+		module, err := modulePath(client, item)
+		if err != nil {
+			return false
+		}
+
+		info, err := client.Latest(module)
+		if err != nil {
+			slog.Debug("Failed to resolve latest version", "package", item.Name, "error", err)
+			return false
+		}
+
+		version = info.Version
+	}
+
+	return version == item.InstalledVersion
+}
+
+// keepBinary copies the binary to the previous binaries dir at the version,
+// removing the copies at versions not in the kept versions.
+func keepBinary(name, version, binDir string, versions []string) error {
+	src := filepath.Join(binDir, name)
+	exists, err := fileExists(src)
+	if err != nil {
+		return fmt.Errorf("failed to check if file exists: %v", err)
+	}
+
+	if !exists {
+		return nil
+	}
+
+	if rootOptions.dryRun {
+		printDryRun("keep previous binary " + src + " in " + previousBinaryPath(name, version))
+		return nil
+	}
+
+	err = os.MkdirAll(filepath.Join(rootOptions.configDir, previousDir), 0o755)
+	if err != nil {
+		return fmt.Errorf("failed to create previous binaries dir: %w", err)
+	}
+
+	err = copyFile(src, previousBinaryPath(name, version))
+	if err != nil {
+		return fmt.Errorf("failed to keep previous binary: %w", err)
+	}
+
+	older, err := filepath.Glob(previousBinaryPath(name, "*"))
+	if err != nil {
+		return err
+	}
+
+	for _, file := range older {
+		if !slices.Contains(versions, strings.TrimPrefix(filepath.Base(file), name+"@")) {
+			_ = removeFile(file)
+		}
+	}
+
+	return nil
+}
+
+// keptBinaries reports whether all the binaries of the package are kept at the
+// version.
+func keptBinaries(item pkg.Package, version string) (bool, error) {
+	names := item.BinaryNames()
+	for _, name := range names {
+		exists, err := fileExists(previousBinaryPath(name, version))
+		if err != nil {
+			return false, fmt.Errorf("failed to check if file exists: %v", err)
+		}
+
+		if !exists {
+			return false, nil
+		}
+	}
+
+	return len(names) > 0, nil
+}
+
+func runRollback(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	item, found := db.GetItem(args[0])
+	if !found {
+		return fmt.Errorf("package %s not found in storage", args[0])
+	}
+
+	previous, found := item.PreviousVersion()
+	if !found {
+		return fmt.Errorf("no previous version of %s recorded", item.Name)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	path = item.InstallDir(path)
+	current := item.InstalledVersion
+	history := item.History[:len(item.History)-1]
+	kept, err := keptBinaries(item, previous.Version)
+	if err != nil {
+		return err
+	}
+
+	if kept {
+		for _, name := range item.BinaryNames() {
+			err = copyFile(previousBinaryPath(name, previous.Version), filepath.Join(path, name))
+			if err != nil {
+				return fmt.Errorf("failed to restore previous binary: %w", err)
+			}
+		}
+
+		if !rootOptions.dryRun {
+			err = item.RecordChecksums(path)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		if item.FromSource() {
+			return fmt.Errorf("no kept binary of %s at %s, packages built from source can only be "+
+				"rolled back when updated with --keep-previous", item.Name, previous.Version)
+		}
+
+		output, err := goInstall(&item, previous.Version, path, claimBinaries(db, item, path, false))
+		if err != nil {
+			return fmt.Errorf("failed to install package %s@%s: %v", item.URI, previous.Version, err)
+		}
+
+		printOutput(output)
+	}
+
+	err = item.ReadBuildInfo(path)
+	if err != nil || rootOptions.dryRun {
+		item.InstalledVersion = previous.Version
+	}
+	item.History = history
+
+	if rollbackOptions.pin {
+		item.Pinned = true
+		item.UpdateVersion(previous.Version)
+	} else {
+		item.UpdatedAt = time.Now()
+	}
+
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return fmt.Errorf("failed to save package %s: %v", item.Name, err)
+	}
+
+	fmt.Println(rootOptions.colorScheme.Header(
+		"Rolled back package " + item.Name + " from " + current + " to " + item.InstalledVersion,
+	))
+
+	return nil
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"github.com/tcondeixa/gomanager/internal/color"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/storage"
 )
 
 const (
@@ -43,6 +46,7 @@ var rootOptions struct {
 	configDir   string
 	storagePath string
 	noColor     bool
+	dryRun      bool
 	colorScheme color.Scheme
 }
 
@@ -80,6 +84,13 @@ func init() {
 		false,
 		"output with colors",
 	)
+
+	rootCmd.PersistentFlags().BoolVar(
+		&rootOptions.dryRun,
+		"dry-run",
+		false,
+		"print what would be done without changing binaries or storage",
+	)
 }
 
 func initLogging() {
@@ -125,6 +136,15 @@ func getConfigDir() {
 	slog.Info("using storage file", "path", rootOptions.storagePath)
 }
 
+func newStorage() *storage.Provider[pkg.Package] {
+	db := storage.New[pkg.Package](rootOptions.storagePath, pkg.Migrations...)
+	if rootOptions.dryRun {
+		db.DryRun(printDryRun)
+	}
+
+	return db
+}
+
 func goBinPath() (string, error) {
 	gobin := os.Getenv("GOBIN")
 	if gobin != "" {
@@ -144,6 +164,15 @@ func goBinPath() (string, error) {
 	return fmt.Sprintf("%s/go/bin", home), nil
 }
 
+func goEnv(key string) (string, error) {
+	output, err := exec.Command("go", "env", key).Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to run go env %s: %w", key, err)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 func colorScheme() {
 	colorSchemeText := os.Getenv(colorSchemeEnv)
 	if colorSchemeText == "" {
@@ -0,0 +1,181 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/manifest"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/storage"
+)
+
+const defaultManifestFileName = binaryName + ".yaml"
+
+var syncOptions struct {
+	filePath string
+	prune    bool
+	jobs     int
+	failFast bool
+}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Sync installed packages with a manifest file",
+	Long: `Sync installed packages with a manifest file, installing missing packages, updating packages
+with a different version and optionally uninstalling packages not in the manifest`,
+	Example:      fmt.Sprintf("  %s sync -f ~/dotfiles/%s --prune", binaryName, defaultManifestFileName),
+	Args:         cobra.NoArgs,
+	SilenceUsage: true,
+	RunE:         runSync,
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+
+	home, err := os.UserHomeDir()
+	cobra.CheckErr(err)
+	syncCmd.Flags().StringVarP(
+		&syncOptions.filePath,
+		"file",
+		"f",
+		filepath.Join(home, defaultManifestFileName),
+		"filepath of the manifest file",
+	)
+
+	syncCmd.Flags().BoolVar(
+		&syncOptions.prune,
+		"prune",
+		false,
+		"uninstall tracked packages not in the manifest",
+	)
+
+	addJobsFlag(syncCmd, &syncOptions.jobs)
+	addFailFastFlag(syncCmd, &syncOptions.failFast)
+}
+
+func runSync(_ *cobra.Command, _ []string) error {
+	m, err := manifest.Load(syncOptions.filePath)
+	if err != nil {
+		return err
+	}
+
+	db := newStorage()
+	err = db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	// packages to install or update are named with their final binary name
+	var installs []pkg.Package
+	var plan []string
+	tracked := db.GetAllItems()
+	for _, entry := range m.Packages {
+		name, err := entry.BinaryName()
+		if err != nil {
+			return err
+		}
+
+		item, exists := tracked[name]
+		delete(tracked, name)
+
+		// a variant keeps following its major version, as it is named after it
+		if exists && item.Alias != "" && entry.Version == "latest" {
+			entry.Version = "~" + variantMajor(item)
+		}
+
+		switch {
+		case !exists:
+			plan = append(plan, "install "+name+" ("+entry.URI+"@"+entry.Version+")")
+			item = pkg.Package{Name: name}
+		case item.URI != entry.URI || item.Version != entry.Version:
+			plan = append(plan, fmt.Sprintf(
+				"update %s (%s -> %s@%s)",
+				name,
+				item.URIWithVersion(),
+				entry.URI,
+				entry.Version,
+			))
+		default:
+			continue
+		}
+
+		item.URI = entry.URI
+		item.Version = entry.Version
+		installs = append(installs, item)
+	}
+
+	var uninstalls []pkg.Package
+	if syncOptions.prune {
+		for _, item := range tracked {
+			plan = append(plan, "uninstall "+item.Name)
+			uninstalls = append(uninstalls, item)
+		}
+	}
+
+	if len(plan) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("Nothing to sync, installed packages match the manifest."))
+		return nil
+	}
+
+	slices.Sort(plan)
+	fmt.Println(rootOptions.colorScheme.Header("Plan:"))
+	for _, step := range plan {
+		fmt.Println(rootOptions.colorScheme.Text("  " + step))
+	}
+
+	slices.SortFunc(uninstalls, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	// packages are pruned first, so their binaries are free for the manifest ones
+	var pruned []jobResult
+	for _, item := range uninstalls {
+		err = uninstallPackage(db, item, path)
+		pruned = append(pruned, jobResult{name: item.Name, err: err})
+	}
+
+	results, err := runJobs(syncOptions.jobs, syncOptions.failFast, installs, func(item pkg.Package) jobResult {
+		output, err := syncPackage(db, item, path)
+		return jobResult{name: item.Name, output: output, err: err}
+	})
+	if err != nil {
+		return err
+	}
+
+	return printJobResults("synced", append(results, pruned...))
+}
+
+// syncPackage installs the package at the manifest version, keeping the
+// recorded history of the tracked package.
+func syncPackage(db *storage.Provider[pkg.Package], item pkg.Package, path string) (string, error) {
+	pack, err := pkg.New(item.URI + "@" + item.Version)
+	if err != nil {
+		return "", err
+	}
+
+	pack.Pinned = item.Pinned
+	pack.Alias = item.Alias
+	pack.InstalledVersion = item.InstalledVersion
+	pack.History = item.History
+	pack.BinDir = item.BinDir
+	pack.Binaries = item.Binaries
+	pack.Tags = item.Tags
+	pack.BuildFlags = item.BuildFlags
+	pack.Env = item.Env
+	pack.Check = item.Check
+	pack.CheckExitCode = item.CheckExitCode
+
+	// a tracked entry is replaced by the manifest one, even when its URI changed
+	_, tracked := db.GetItem(item.ID())
+
+	return installPackage(db, pack, path, item.Name, tracked)
+}
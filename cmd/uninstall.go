@@ -28,7 +28,7 @@ func installedPackagesCompletion(
 	args []string,
 	_ string,
 ) ([]cobra.Completion, cobra.ShellCompDirective) {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return []cobra.Completion{}, cobra.ShellCompDirectiveError
@@ -49,7 +49,7 @@ func init() {
 }
 
 func runUninstall(_ *cobra.Command, args []string) error {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return err
@@ -68,14 +68,7 @@ func runUninstall(_ *cobra.Command, args []string) error {
 			return fmt.Errorf("package %s not found in storage", name)
 		}
 
-		binPath := filepath.Join(path, item.Name)
-		err := os.Remove(binPath)
-		if err != nil {
-			return fmt.Errorf("failed to remove binary at %s: %w", binPath, err)
-		}
-		slog.Info("Removed binary", "path", binPath)
-
-		err = db.DeleteItem(item.ID())
+		err = uninstallPackage(db, item, path)
 		if err != nil {
 			return err
 		}
@@ -85,3 +78,53 @@ func runUninstall(_ *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// uninstallPackage removes the binaries not installed by other packages, with
+// their kept previous binaries, and the package from storage.
+func uninstallPackage(db *storage.Provider[pkg.Package], item pkg.Package, path string) error {
+	err := unlinkVariant(item, path)
+	if err != nil {
+		return fmt.Errorf("failed to remove link of %s: %w", item.Alias, err)
+	}
+
+	for _, name := range item.BinaryNames() {
+		binPath := filepath.Join(item.InstallDir(path), name)
+		owner, found := binaryOwner(db, item, name)
+		if found {
+			slog.Info("Keeping binary installed by another package", "path", binPath, "package", owner.Name)
+			continue
+		}
+
+		err := removeFile(binPath)
+		switch {
+		case os.IsNotExist(err):
+			// still untrack it, otherwise it could never be uninstalled
+			slog.Warn("Binary already removed", "path", binPath)
+		case err != nil:
+			return fmt.Errorf("failed to remove binary at %s: %w", binPath, err)
+		default:
+			slog.Info("Removed binary", "path", binPath)
+		}
+
+		previous, err := filepath.Glob(previousBinaryPath(name, "*"))
+		if err == nil {
+			for _, file := range previous {
+				_ = removeFile(file)
+			}
+		}
+	}
+
+	return db.DeleteItem(item.ID())
+}
+
+// binaryOwner returns the other tracked package installing the binary name, as
+// a binary of a group can be taken over by a package of its own.
+func binaryOwner(db *storage.Provider[pkg.Package], item pkg.Package, name string) (pkg.Package, bool) {
+	for _, other := range db.GetAllItems() {
+		if other.ID() != item.ID() && slices.Contains(other.BinaryNames(), name) {
+			return other, true
+		}
+	}
+
+	return pkg.Package{}, false
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/constraint"
+)
+
+var unpinCmd = &cobra.Command{
+	Use:               "unpin <name> [version]",
+	Short:             "Unpin package",
+	Long:              `Unpin package so it is updated again, tracking a version or constraint (default to latest)`,
+	Example:           fmt.Sprintf("  %s unpin %s '~0.2'", binaryName, binaryName),
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: firstArgPackageCompletion,
+	RunE:              runUnpin,
+}
+
+func init() {
+	rootCmd.AddCommand(unpinCmd)
+}
+
+func runUnpin(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	item, found := db.GetItem(args[0])
+	if !found {
+		return fmt.Errorf("package %s not found in storage", args[0])
+	}
+
+	version := "latest"
+	if len(args) > 1 {
+		version = args[1]
+	}
+
+	if constraint.IsConstraint(version) {
+		_, err = constraint.Parse(version)
+		if err != nil {
+			return err
+		}
+	}
+
+	item.Pinned = false
+	item.UpdateVersion(version)
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return fmt.Errorf("failed to save unpinned package %s: %v", item.Name, err)
+	}
+
+	fmt.Println(rootOptions.colorScheme.Header("Unpinned package " + item.Name + ", now tracking " + version))
+
+	return nil
+}
@@ -3,8 +3,12 @@ package cmd
 import (
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/constraint"
 	"github.com/tcondeixa/gomanager/internal/pkg"
 	"github.com/tcondeixa/gomanager/internal/storage"
 )
@@ -12,14 +16,18 @@ import (
 var updateOptions struct {
 	name           string
 	forceNonLatest bool
+	jobs           int
+	failFast       bool
+	keepPrevious   bool
 }
 
 var updateCmd = &cobra.Command{
-	Use:     "update",
-	Short:   "Update packages",
-	Long:    `Update packages`,
-	Example: fmt.Sprintf("  %s update --name %s", binaryName, binaryName),
-	RunE:    runUpdate,
+	Use:          "update",
+	Short:        "Update packages",
+	Long:         `Update packages`,
+	Example:      fmt.Sprintf("  %s update --name %s", binaryName, binaryName),
+	SilenceUsage: true,
+	RunE:         runUpdate,
 }
 
 func init() {
@@ -44,60 +52,135 @@ func init() {
 		"force",
 		"f",
 		false,
-		"force also non-latest versions",
+		"force also non-latest versions, except pinned ones",
 	)
+
+	updateCmd.Flags().BoolVar(
+		&updateOptions.keepPrevious,
+		"keep-previous",
+		false,
+		"keep the previous binary so rollback does not need to build it",
+	)
+
+	addJobsFlag(updateCmd, &updateOptions.jobs)
+	addFailFastFlag(updateCmd, &updateOptions.failFast)
 }
 
 func runUpdate(_ *cobra.Command, _ []string) error {
-	db := storage.New[pkg.Package](rootOptions.storagePath)
+	db := newStorage()
 	err := db.Start()
 	if err != nil {
 		return fmt.Errorf("failed to load storage: %w", err)
 	}
 
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	var all []pkg.Package
 	if updateOptions.name != "" {
 		item, found := db.GetItem(updateOptions.name)
 		if !found {
 			return fmt.Errorf("package %s not found in storage", updateOptions.name)
 		}
 
-		item.UpdateVersion("latest")
-		output, err := item.Install()
-		if err != nil {
-			return fmt.Errorf("failed to install package %s: %v", item, err)
+		all = append(all, item)
+	} else {
+		all = slices.Collect(maps.Values(db.GetAllItems()))
+	}
+
+	var items []pkg.Package
+	var skipped []jobResult
+	for _, item := range all {
+		reason := updateSkipReason(item)
+		if reason != "" {
+			skipped = append(skipped, jobResult{name: item.Name, skipped: reason})
+			continue
 		}
 
-		fmt.Println(rootOptions.colorScheme.Text(output))
+		items = append(items, item)
+	}
 
-		item.UpdateVersion("latest")
-		err = db.SaveItem(item.ID(), item)
-		if err != nil {
-			return fmt.Errorf("failed to save updated package %s: %v", item, err)
-		}
+	slices.SortFunc(items, func(a, b pkg.Package) int {
+		return strings.Compare(a.Name, b.Name)
+	})
 
-		fmt.Println("Package updated successfully")
+	results, err := runJobs(updateOptions.jobs, updateOptions.failFast, items, func(item pkg.Package) jobResult {
+		output, err := updatePackage(db, item, path)
+		return jobResult{name: item.Name, output: output, err: err}
+	})
+	if err != nil {
+		return err
 	}
 
-	items := db.GetAllItems()
-	for _, item := range items {
-		if item.Version == "latest" || updateOptions.forceNonLatest {
-			slog.Info("Updating package", "package", item.URI, "current_version", item.Version)
-			item.UpdateVersion("latest")
-			output, err := item.Install()
-			if err != nil {
-				return fmt.Errorf("failed to install package %s: %v", item, err)
-			}
+	slices.SortFunc(skipped, func(a, b jobResult) int {
+		return strings.Compare(a.name, b.name)
+	})
 
-			fmt.Println(rootOptions.colorScheme.Text(output))
+	return printJobResults("updated", append(results, skipped...))
+}
 
-			err = db.SaveItem(item.ID(), item)
-			if err != nil {
-				return fmt.Errorf("failed to save updated package %s: %v", item, err)
-			}
+// updateSkipReason returns why the package must not be updated, if any.
+// Pinned packages are never updated, while packages with an exact version are
+// only moved to latest when forced or when explicitly selected by name.
+// Packages from source are always rebuilt from their dir or git ref, and
+// variants are always updated within their major version.
+func updateSkipReason(item pkg.Package) string {
+	switch {
+	case item.Pinned:
+		return "pinned at " + item.Version + ", use unpin to update"
+	case item.FromSource() || item.Alias != "":
+		return ""
+	case item.Version == "latest" || constraint.IsConstraint(item.Version):
+		return ""
+	case updateOptions.forceNonLatest || updateOptions.name != "":
+		return ""
+	default:
+		return "version " + item.Version + " is not latest, use --force to update"
+	}
+}
+
+// updateVersion returns the version the package tracks once updated.
+func updateVersion(item pkg.Package) string {
+	switch {
+	case item.FromSource() || constraint.IsConstraint(item.Version):
+		return item.Version
+	case item.Alias != "":
+		// variants are named after their major version, which must not change
+		return "~" + variantMajor(item)
+	default:
+		return "latest"
+	}
+}
+
+func updatePackage(db *storage.Provider[pkg.Package], item pkg.Package, path string) (string, error) {
+	slog.Info("Updating package", "package", item.URI, "current_version", item.Version)
+	item.UpdateVersion(updateVersion(item))
+
+	version, err := targetVersion(item)
+	if err != nil {
+		return "", err
+	}
 
-			fmt.Println(rootOptions.colorScheme.Text("Package " + item.Name + " updated successfully"))
+	path = item.InstallDir(path)
+	if updateOptions.keepPrevious {
+		err = backupBinary(item, version, path)
+		if err != nil {
+			return "", err
 		}
 	}
 
-	return nil
+	output, err := goInstall(&item, version, path, claimBinaries(db, item, path, false))
+	if err != nil {
+		return "", fmt.Errorf("failed to install package %s: %v", item.URIWithVersion(), err)
+	}
+
+	readBuildInfo(&item, path)
+	err = db.SaveItem(item.ID(), item)
+	if err != nil {
+		return output, fmt.Errorf("failed to save updated package %s: %v", item.Name, err)
+	}
+
+	return output, nil
 }
@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"github.com/tcondeixa/gomanager/internal/storage"
+	"golang.org/x/mod/semver"
+)
+
+var useCmd = &cobra.Command{
+	Use:   "use <name> <version>",
+	Short: "Switch the version used for a tool name",
+	Long: `Switch the version used for a tool name, linking the name to the variant installed
+with install --versioned, given by its major version or installed version`,
+	Example:           fmt.Sprintf("  %s use golangci-lint v1", binaryName),
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: variantCompletion,
+	SilenceUsage:      true,
+	RunE:              runUse,
+}
+
+func init() {
+	rootCmd.AddCommand(useCmd)
+}
+
+func variantCompletion(
+	_ *cobra.Command,
+	args []string,
+	_ string,
+) ([]cobra.Completion, cobra.ShellCompDirective) {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return []cobra.Completion{}, cobra.ShellCompDirectiveError
+	}
+
+	completions := []cobra.Completion{}
+	for _, item := range db.GetAllItems() {
+		switch {
+		case item.Alias == "":
+		case len(args) == 0:
+			completions = append(completions, item.Alias)
+		case len(args) == 1 && item.Alias == args[0]:
+			completions = append(completions, variantMajor(item))
+		}
+	}
+	slices.Sort(completions)
+
+	return slices.Compact(completions), cobra.ShellCompDirectiveNoFileComp
+}
+
+func runUse(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	alias, version := args[0], args[1]
+	for _, item := range db.GetAllItems() {
+		if item.Alias != alias || (variantMajor(item) != version && item.InstalledVersion != version) {
+			continue
+		}
+
+		err = linkVariant(item, path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(rootOptions.colorScheme.Header(
+			"Using " + item.Name + " (" + item.InstalledVersion + ") as " + alias,
+		))
+
+		return nil
+	}
+
+	return fmt.Errorf("no variant of %s at %s installed, use install --versioned to install it", alias, version)
+}
+
+// installVariant installs the package under a name with its major version, so
+// other major versions can be installed side by side. The variant is used for
+// the tool name when nothing has that name yet.
+func installVariant(db *storage.Provider[pkg.Package], pack *pkg.Package, path string) (string, error) {
+	version, err := variantVersion(*pack)
+	if err != nil {
+		return "", err
+	}
+
+	// the variant must keep its major version on update
+	major := semver.Major(version)
+	if pack.Version == "latest" {
+		pack.Version = "~" + major
+	}
+
+	pack.Alias = pack.Name
+	output, err := installPackage(db, pack, path, pack.Alias+"-"+major, installOptions.force)
+	if err != nil {
+		return output, err
+	}
+
+	_, err = os.Lstat(filepath.Join(pack.InstallDir(path), pack.Alias))
+	if os.IsNotExist(err) {
+		err = linkVariant(*pack, path)
+		if err != nil {
+			return output, err
+		}
+	}
+
+	return output, nil
+}
+
+// variantVersion resolves the version the variant is named after, which must
+// be a release, as the name cannot follow a branch or a commit.
+func variantVersion(pack pkg.Package) (string, error) {
+	if pack.FromSource() {
+		return "", fmt.Errorf("cannot install %s as a versioned variant, as it is built from source", pack.Name)
+	}
+
+	version, err := targetVersion(pack)
+	if err != nil {
+		return "", err
+	}
+
+	if version == "latest" {
+		client, err := proxyClient()
+		if err != nil {
+			return "", err
+		}
+
+		module, err := modulePath(client, pack)
+		if err != nil {
+			return "", err
+		}
+
+		info, err := client.Latest(module)
+		if err != nil {
+			return "", fmt.Errorf("failed to get latest version of %s: %w", module, err)
+		}
+
+		version = info.Version
+	}
+
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("cannot name a variant of %s after version %s, use a release version", pack.Name, version)
+	}
+
+	return version, nil
+}
+
+// variantMajor returns the major version the variant is named after.
+func variantMajor(item pkg.Package) string {
+	return strings.TrimPrefix(item.Name, item.Alias+"-")
+}
+
+// linkVariant points the tool name to the variant, refusing to replace a file
+// that is not a link, as it is not a variant.
+func linkVariant(item pkg.Package, path string) error {
+	link := filepath.Join(item.InstallDir(path), item.Alias)
+	info, err := os.Lstat(link)
+	switch {
+	case err == nil && info.Mode()&os.ModeSymlink == 0:
+		return fmt.Errorf("%s is not a link, remove it to use a variant of %s", link, item.Alias)
+	case err != nil && !os.IsNotExist(err):
+		return fmt.Errorf("failed to check %s: %w", link, err)
+	}
+
+	err = linkFile(item.Name, link)
+	if err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", link, item.Name, err)
+	}
+
+	return nil
+}
+
+// unlinkVariant removes the link of the tool name when it points to the variant.
+func unlinkVariant(item pkg.Package, path string) error {
+	if item.Alias == "" {
+		return nil
+	}
+
+	link := filepath.Join(item.InstallDir(path), item.Alias)
+	target, err := os.Readlink(link)
+	if err != nil || target != item.Name {
+		return nil
+	}
+
+	return removeFile(link)
+}
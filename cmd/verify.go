@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"debug/buildinfo"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+)
+
+const (
+	verifyOK          = "ok"
+	verifyMissing     = "missing"
+	verifyUnreadable  = "unreadable"
+	verifyModified    = "modified"
+	verifySumMismatch = "sum_mismatch"
+	verifyUnrecorded  = "unrecorded"
+)
+
+var verifyOptions struct {
+	outputFormat string
+}
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [name...]",
+	Short: "Verify installed binaries against the recorded checksums",
+	Long: `Verify installed binaries against the SHA-256 checksums and module sums recorded at install,
+exiting with failure if any binary is missing, unreadable, modified or built from a module with another sum
+(default all packages)`,
+	Example:           fmt.Sprintf("  %s verify -o json", binaryName),
+	ValidArgsFunction: installedPackagesCompletion,
+	SilenceUsage:      true,
+	RunE:              runVerify,
+}
+
+type verifyResult struct {
+	Name     string `json:"name"`
+	Binary   string `json:"binary"`
+	Path     string `json:"path"`
+	Status   string `json:"status"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	// SumStatus is the comparison of the module sum in the build info with the
+	// recorded one, empty when no sum was recorded.
+	SumStatus   string `json:"sum_status,omitempty"`
+	ExpectedSum string `json:"expected_sum,omitempty"`
+	ActualSum   string `json:"actual_sum,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// failed reports whether the binary is not the one installed.
+func (r verifyResult) failed() bool {
+	return (r.Status != verifyOK && r.Status != verifyUnrecorded) || r.SumStatus == verifySumMismatch
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().StringVarP(
+		&verifyOptions.outputFormat,
+		"output",
+		"o",
+		"text",
+		"Output format: "+strings.Join(availableOutputs, ", "),
+	)
+	cobra.CheckErr(verifyCmd.RegisterFlagCompletionFunc(
+		"output",
+		cobra.FixedCompletions(availableOutputs, cobra.ShellCompDirectiveDefault),
+	))
+}
+
+func runVerify(_ *cobra.Command, args []string) error {
+	db := newStorage()
+	err := db.Start()
+	if err != nil {
+		return fmt.Errorf("failed to load storage: %w", err)
+	}
+
+	path, err := goBinPath()
+	if err != nil {
+		return fmt.Errorf("failed to determine go bin path: %v", err)
+	}
+
+	var items []pkg.Package
+	for _, name := range args {
+		item, found := db.GetItem(name)
+		if !found {
+			return fmt.Errorf("package %s not found in storage", name)
+		}
+
+		items = append(items, item)
+	}
+
+	if len(args) == 0 {
+		items = slices.Collect(maps.Values(db.GetAllItems()))
+	}
+
+	results := []verifyResult{}
+	for _, item := range items {
+		for _, name := range item.BinaryNames() {
+			results = append(results, verifyBinary(item, name, filepath.Join(item.InstallDir(path), name)))
+		}
+	}
+
+	slices.SortFunc(results, func(a, b verifyResult) int {
+		return strings.Compare(a.Name+"/"+a.Binary, b.Name+"/"+b.Binary)
+	})
+
+	switch verifyOptions.outputFormat {
+	case "json":
+		err = printVerifyAsJSON(results)
+	case "text":
+		err = printVerifyAsText(results)
+	}
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.failed() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d binaries failed verification", failed)
+	}
+
+	return nil
+}
+
+// verifyBinary compares the binary with the checksum recorded at install, and
+// independently its build info with the recorded module sum, so a rebuilt
+// binary is reported as modified and with a sum mismatch.
+func verifyBinary(item pkg.Package, name, binPath string) verifyResult {
+	result := verifyResult{Name: item.Name, Binary: name, Path: binPath, Expected: item.Checksums[name]}
+	exists, err := fileExists(binPath)
+	switch {
+	case err != nil:
+		result.Status = verifyUnreadable
+		result.Error = err.Error()
+		return result
+	case !exists:
+		result.Status = verifyMissing
+		return result
+	}
+
+	result.Actual, err = pkg.FileChecksum(binPath)
+	switch {
+	case err != nil:
+		result.Status = verifyUnreadable
+		result.Error = err.Error()
+		return result
+	case result.Expected == "":
+		result.Status = verifyUnrecorded
+	case result.Actual != result.Expected:
+		result.Status = verifyModified
+	default:
+		result.Status = verifyOK
+	}
+
+	if item.Sum == "" {
+		return result
+	}
+
+	// a binary without build info, or built from a checkout, has no module sum
+	result.ExpectedSum = item.Sum
+	info, err := buildinfo.ReadFile(binPath)
+	if err == nil {
+		result.ActualSum = info.Main.Sum
+	}
+
+	result.SumStatus = verifyOK
+	if result.ActualSum != result.ExpectedSum {
+		result.SumStatus = verifySumMismatch
+	}
+
+	return result
+}
+
+func printVerifyAsJSON(results []verifyResult) error {
+	bytes, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode verify results to json: %w", err)
+	}
+
+	fmt.Println(string(bytes))
+
+	return nil
+}
+
+func printVerifyAsText(results []verifyResult) error {
+	if len(results) == 0 {
+		fmt.Println(rootOptions.colorScheme.Text("No binaries to verify."))
+		return nil
+	}
+
+	rows := [][]string{{"NAME", "BINARY", "STATUS", "PATH"}}
+	for _, result := range results {
+		status := result.Status
+		if status == verifyUnrecorded {
+			status += " (reinstall to record the checksum)"
+		}
+
+		if result.SumStatus == verifySumMismatch {
+			status += ", " + verifySumMismatch
+		}
+
+		rows = append(rows, []string{result.Name, result.Binary, status, result.Path})
+	}
+
+	return printTable(rows)
+}
@@ -0,0 +1,165 @@
+package constraint
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// operators sorted so that the longest prefix is matched first
+var operators = []string{">=", "<=", ">", "<", "=", "~", "^"}
+
+type comparator struct {
+	op      string
+	version string
+}
+
+// Constraint is a set of comparators that a version must all satisfy,
+// written separated by commas, for example ">=v1.2.0, <v2".
+type Constraint struct {
+	raw         string
+	comparators []comparator
+}
+
+// IsConstraint reports whether the version is a constraint instead of an
+// exact version or a version query understood by go install, like latest.
+func IsConstraint(version string) bool {
+	version = strings.TrimSpace(version)
+	for _, op := range operators {
+		if strings.HasPrefix(version, op) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func Parse(text string) (Constraint, error) {
+	c := Constraint{raw: text}
+	for part := range strings.SplitSeq(text, ",") {
+		part = strings.TrimSpace(part)
+		op := ""
+		for _, candidate := range operators {
+			if strings.HasPrefix(part, candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		if op == "" {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: missing operator in %q", text, part)
+		}
+
+		version := strings.TrimSpace(strings.TrimPrefix(part, op))
+		if !strings.HasPrefix(version, "v") {
+			version = "v" + version
+		}
+
+		if !semver.IsValid(version) {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: invalid version %q", text, version)
+		}
+
+		comparators, err := expand(op, version)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", text, err)
+		}
+
+		c.comparators = append(c.comparators, comparators...)
+	}
+
+	return c, nil
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+// Check reports whether the version satisfies the constraint.
+// Pre-release versions are never selected by a constraint.
+func (c Constraint) Check(version string) bool {
+	if !semver.IsValid(version) || semver.Prerelease(version) != "" {
+		return false
+	}
+
+	for _, cmp := range c.comparators {
+		result := semver.Compare(version, cmp.version)
+		ok := false
+		switch cmp.op {
+		case ">=":
+			ok = result >= 0
+		case "<=":
+			ok = result <= 0
+		case ">":
+			ok = result > 0
+		case "<":
+			ok = result < 0
+		case "=":
+			ok = result == 0
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Latest returns the newest of the versions satisfying the constraint.
+func (c Constraint) Latest(versions []string) (string, bool) {
+	latest := ""
+	for _, version := range versions {
+		if c.Check(version) && (latest == "" || semver.Compare(version, latest) > 0) {
+			latest = version
+		}
+	}
+
+	return latest, latest != ""
+}
+
+// expand converts the tilde and caret operators into a lower and upper bound.
+// ~1.2.3 and ~1.2 allow patch updates and ~1 minor updates.
+// ^1.2.3 allows updates not changing the major version, or the minor version for v0.
+func expand(op, version string) ([]comparator, error) {
+	switch op {
+	case "~", "^":
+	case "=":
+		return []comparator{{op: op, version: semver.Canonical(version)}}, nil
+	default:
+		return []comparator{{op: op, version: version}}, nil
+	}
+
+	canonical := semver.Canonical(version)
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSuffix(canonical, semver.Prerelease(canonical)), "v"), ".")
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+
+	// number of components written, as v1.2 is valid shorthand for v1.2.0
+	written := strings.Count(strings.TrimSuffix(version, semver.Prerelease(version)+semver.Build(version)), ".") + 1
+
+	var upper string
+	switch {
+	case op == "~" && written == 1:
+		upper = fmt.Sprintf("v%d.0.0", major+1)
+	case op == "~":
+		upper = fmt.Sprintf("v%d.%d.0", major, minor+1)
+	case major > 0 || written == 1:
+		upper = fmt.Sprintf("v%d.0.0", major+1)
+	default:
+		upper = fmt.Sprintf("v0.%d.0", minor+1)
+	}
+
+	return []comparator{
+		{op: ">=", version: canonical},
+		{op: "<", version: upper},
+	}, nil
+}
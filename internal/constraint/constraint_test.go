@@ -0,0 +1,93 @@
+package constraint
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text    string
+		wantErr bool
+	}{
+		{"^v1.2.3", false},
+		{"~1.2", false},
+		{">=v1.2.0, <v2", false},
+		{"=v1.2.3", false},
+		{"v1.2.3", true},
+		{">=v1.2.0, v2", true},
+		{"^vx.y", true},
+		{"~", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			_, err := Parse(tt.text)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", tt.text, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		op      string
+		version string
+		want    []comparator
+	}{
+		{"~", "v1.2.3", []comparator{{">=", "v1.2.3"}, {"<", "v1.3.0"}}},
+		{"~", "v1.2", []comparator{{">=", "v1.2.0"}, {"<", "v1.3.0"}}},
+		{"~", "v1", []comparator{{">=", "v1.0.0"}, {"<", "v2.0.0"}}},
+		{"^", "v1.2.3", []comparator{{">=", "v1.2.3"}, {"<", "v2.0.0"}}},
+		{"^", "v0.2.3", []comparator{{">=", "v0.2.3"}, {"<", "v0.3.0"}}},
+		{"^", "v0", []comparator{{">=", "v0.0.0"}, {"<", "v1.0.0"}}},
+		{"^", "v1.2.3-rc.1", []comparator{{">=", "v1.2.3-rc.1"}, {"<", "v2.0.0"}}},
+		{"=", "v1.2", []comparator{{"=", "v1.2.0"}}},
+		{">=", "v1.2", []comparator{{">=", "v1.2"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.op+tt.version, func(t *testing.T) {
+			got, err := expand(tt.op, tt.version)
+			if err != nil {
+				t.Fatalf("expand(%q, %q) error = %v", tt.op, tt.version, err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expand(%q, %q) = %v, want %v", tt.op, tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLatest(t *testing.T) {
+	versions := []string{"v0.9.0", "v1.0.0", "v1.2.0", "v1.2.5", "v1.3.0-rc.1", "v1.3.0", "v2.0.0"}
+	tests := []struct {
+		text  string
+		want  string
+		found bool
+	}{
+		{"^v1.2", "v1.3.0", true},
+		{"~v1.2", "v1.2.5", true},
+		{"~v1", "v1.3.0", true},
+		{">=v1.0.0, <v1.3.0", "v1.2.5", true},
+		{"^v0.9", "v0.9.0", true},
+		{"=v1.2", "v1.2.0", true},
+		{">v2", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			c, err := Parse(tt.text)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.text, err)
+			}
+
+			got, found := c.Latest(versions)
+			if got != tt.want || found != tt.found {
+				t.Errorf("Latest() for %q = %q, %v, want %q, %v", tt.text, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/tcondeixa/gomanager/internal/constraint"
+	"github.com/tcondeixa/gomanager/internal/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a package to install, with the version defaulting to latest and
+// the name defaulting to the go install name.
+type Entry struct {
+	URI     string `yaml:"uri"`
+	Version string `yaml:"version,omitempty"`
+	Name    string `yaml:"name,omitempty"`
+}
+
+type Manifest struct {
+	Packages []Entry `yaml:"packages"`
+}
+
+func Load(path string) (*Manifest, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest file: %w", err)
+	}
+
+	var m Manifest
+	err = yaml.Unmarshal(bytes, &m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest file: %w", err)
+	}
+
+	names := map[string]bool{}
+	for i, entry := range m.Packages {
+		if entry.URI == "" {
+			return nil, fmt.Errorf("invalid manifest entry %d: missing uri", i+1)
+		}
+
+		if entry.Version == "" {
+			m.Packages[i].Version = "latest"
+		}
+
+		if constraint.IsConstraint(m.Packages[i].Version) {
+			_, err = constraint.Parse(m.Packages[i].Version)
+			if err != nil {
+				return nil, fmt.Errorf("invalid manifest entry %s: %w", entry.URI, err)
+			}
+		}
+
+		name, err := m.Packages[i].BinaryName()
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest entry %s: %w", entry.URI, err)
+		}
+
+		if names[name] {
+			return nil, fmt.Errorf("invalid manifest: duplicated name %s", name)
+		}
+		names[name] = true
+	}
+
+	return &m, nil
+}
+
+// Package returns the package of the entry, named as go install names it,
+// as the entry name is given to the binary after installing it.
+func (e Entry) Package() (*pkg.Package, error) {
+	return pkg.New(e.URI + "@" + e.Version)
+}
+
+// BinaryName returns the name of the binary installed for the entry.
+func (e Entry) BinaryName() (string, error) {
+	if e.Name != "" {
+		return e.Name, nil
+	}
+
+	pack, err := e.Package()
+	if err != nil {
+		return "", err
+	}
+
+	return pack.Name, nil
+}
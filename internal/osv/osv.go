@@ -0,0 +1,252 @@
+package osv
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+const (
+	// ecosystem of the entries matched, as the module paths are Go ones.
+	ecosystem = "Go"
+	// Stdlib is the module of the go standard library in the Go database.
+	Stdlib = "stdlib"
+)
+
+// Entry is a vulnerability in the OSV format, with only the fields needed to
+// match module versions.
+type Entry struct {
+	ID        string     `json:"id"`
+	Summary   string     `json:"summary"`
+	Aliases   []string   `json:"aliases"`
+	Withdrawn *time.Time `json:"withdrawn"`
+	Affected  []Affected `json:"affected"`
+}
+
+type Affected struct {
+	Package struct {
+		Name      string `json:"name"`
+		Ecosystem string `json:"ecosystem"`
+	} `json:"package"`
+	Ranges []Range `json:"ranges"`
+}
+
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+}
+
+// Finding is a vulnerability affecting a module version.
+type Finding struct {
+	ID      string   `json:"id"`
+	Aliases []string `json:"aliases,omitempty"`
+	Summary string   `json:"summary,omitempty"`
+	Module  string   `json:"module"`
+	Version string   `json:"version"`
+	// Fixed is the first version fixing the vulnerability, empty if there is none.
+	Fixed string `json:"fixed,omitempty"`
+}
+
+// Database is a set of vulnerabilities indexed by module path.
+type Database struct {
+	modules map[string][]*Entry
+}
+
+// Load reads the OSV entries from a dir or a zip file, like the offline copy
+// of the Go vulnerability database. Files that are not entries, such as the
+// database indexes, are skipped.
+func Load(dbPath string) (*Database, error) {
+	info, err := os.Stat(dbPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open vulnerability database: %w", err)
+	}
+
+	var fsys fs.FS
+	if info.IsDir() {
+		fsys = os.DirFS(dbPath)
+	} else {
+		r, err := zip.OpenReader(dbPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open vulnerability database %s: %w", dbPath, err)
+		}
+		defer r.Close()
+
+		fsys = r
+	}
+
+	db := &Database{modules: map[string][]*Entry{}}
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || path.Ext(name) != ".json" {
+			return nil
+		}
+
+		return db.add(fsys, name)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read vulnerability database %s: %w", dbPath, err)
+	}
+
+	if len(db.modules) == 0 {
+		return nil, fmt.Errorf("no %s vulnerabilities found in %s", ecosystem, filepath.Clean(dbPath))
+	}
+
+	return db, nil
+}
+
+func (db *Database) add(fsys fs.FS, name string) error {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return err
+	}
+
+	var entry Entry
+	err = json.Unmarshal(data, &entry)
+	if err != nil || entry.ID == "" || len(entry.Affected) == 0 {
+		slog.Debug("Skipping file without vulnerability", "file", name)
+		return nil
+	}
+
+	if entry.Withdrawn != nil {
+		return nil
+	}
+
+	for _, affected := range entry.Affected {
+		module := affected.Package.Name
+		if affected.Package.Ecosystem != ecosystem || slices.Contains(db.modules[module], &entry) {
+			continue
+		}
+
+		db.modules[module] = append(db.modules[module], &entry)
+	}
+
+	return nil
+}
+
+// Query returns the vulnerabilities affecting the version of the module.
+func (db *Database) Query(module, version string) []Finding {
+	if !semver.IsValid(version) {
+		return nil
+	}
+
+	var findings []Finding
+	for _, entry := range db.modules[module] {
+		for _, affected := range entry.Affected {
+			if affected.Package.Ecosystem != ecosystem || affected.Package.Name != module {
+				continue
+			}
+
+			fixed, found := affects(affected.Ranges, version)
+			if !found {
+				continue
+			}
+
+			findings = append(findings, Finding{
+				ID:      entry.ID,
+				Aliases: entry.Aliases,
+				Summary: entry.Summary,
+				Module:  module,
+				Version: version,
+				Fixed:   fixed,
+			})
+			break
+		}
+	}
+
+	return findings
+}
+
+// affects reports whether the version is in one of the semver ranges, with
+// the version fixing it.
+func affects(ranges []Range, version string) (string, bool) {
+	for _, r := range ranges {
+		if r.Type != "SEMVER" {
+			continue
+		}
+
+		events := slices.Clone(r.Events)
+		slices.SortStableFunc(events, func(a, b Event) int {
+			return semver.Compare(eventVersion(a), eventVersion(b))
+		})
+
+		affected := false
+		fixed := ""
+		for _, e := range events {
+			switch {
+			case e.Introduced != "":
+				if e.Introduced == "0" || semver.Compare(version, canonical(e.Introduced)) >= 0 {
+					affected = true
+					fixed = ""
+				}
+			case e.Fixed != "":
+				if !affected {
+					continue
+				}
+
+				if semver.Compare(version, canonical(e.Fixed)) >= 0 {
+					affected = false
+				} else if fixed == "" {
+					fixed = canonical(e.Fixed)
+				}
+			case e.LastAffected != "":
+				if affected && semver.Compare(version, canonical(e.LastAffected)) > 0 {
+					affected = false
+				}
+			}
+		}
+
+		if affected {
+			return fixed, true
+		}
+	}
+
+	return "", false
+}
+
+func eventVersion(e Event) string {
+	switch {
+	case e.Introduced == "0":
+		return "v0.0.0-0"
+	case e.Introduced != "":
+		return canonical(e.Introduced)
+	case e.Fixed != "":
+		return canonical(e.Fixed)
+	default:
+		return canonical(e.LastAffected)
+	}
+}
+
+// canonical adds the v prefix of go versions, missing in the OSV ranges.
+func canonical(version string) string {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+
+	return version
+}
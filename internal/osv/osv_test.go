@@ -0,0 +1,96 @@
+package osv
+
+import "testing"
+
+func TestAffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		ranges  []Range
+		version string
+		want    bool
+		wantFix string
+	}{
+		{
+			name:    "introduced at zero and not fixed",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}}}},
+			version: "v1.0.0",
+			want:    true,
+		},
+		{
+			name:    "before fix",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}}},
+			version: "v1.1.9",
+			want:    true,
+			wantFix: "v1.2.0",
+		},
+		{
+			name:    "at fix",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {Fixed: "1.2.0"}}}},
+			version: "v1.2.0",
+			want:    false,
+		},
+		{
+			name:    "before introduced",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "1.1.0"}, {Fixed: "1.2.0"}}}},
+			version: "v1.0.5",
+			want:    false,
+		},
+		{
+			name: "second range of unsorted events",
+			ranges: []Range{{Type: "SEMVER", Events: []Event{
+				{Fixed: "1.3.0"},
+				{Introduced: "1.2.5"},
+				{Fixed: "1.1.0"},
+				{Introduced: "0"},
+			}}},
+			version: "v1.2.7",
+			want:    true,
+			wantFix: "v1.3.0",
+		},
+		{
+			name: "between ranges",
+			ranges: []Range{{Type: "SEMVER", Events: []Event{
+				{Introduced: "0"},
+				{Fixed: "1.1.0"},
+				{Introduced: "1.2.5"},
+				{Fixed: "1.3.0"},
+			}}},
+			version: "v1.2.0",
+			want:    false,
+		},
+		{
+			name:    "at last affected",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "1.0.0"}, {LastAffected: "1.4.0"}}}},
+			version: "v1.4.0",
+			want:    true,
+		},
+		{
+			name:    "after last affected",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "1.0.0"}, {LastAffected: "1.4.0"}}}},
+			version: "v1.4.1",
+			want:    false,
+		},
+		{
+			name:    "pre-release before fix",
+			ranges:  []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {Fixed: "1.22.0"}}}},
+			version: "v1.22.0-rc.1",
+			want:    true,
+			wantFix: "v1.22.0",
+		},
+		{
+			name:    "ranges other than semver are skipped",
+			ranges:  []Range{{Type: "GIT", Events: []Event{{Introduced: "0"}}}},
+			version: "v1.0.0",
+			want:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fixed, got := affects(tt.ranges, tt.version)
+			if got != tt.want || fixed != tt.wantFix {
+				t.Errorf("affects(%s) = %q, %v, want %q, %v", tt.version, fixed, got, tt.wantFix, tt.want)
+			}
+		})
+	}
+}
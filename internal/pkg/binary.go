@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// checkPackagePath validates the package path given to go install, which can
+// end with the /... wildcard.
+func checkPackagePath(pkgPath string) error {
+	pkgPath = strings.TrimSuffix(pkgPath, groupSuffix)
+	err := module.CheckImportPath(pkgPath)
+	if err != nil {
+		return fmt.Errorf("invalid package path: %w", err)
+	}
+
+	first, _, _ := strings.Cut(pkgPath, "/")
+	if !strings.Contains(first, ".") {
+		return fmt.Errorf("invalid package path %s: missing dot in first path element", pkgPath)
+	}
+
+	return nil
+}
+
+// getBinaryNameFromURI returns the name go install gives to the binary of the
+// package, the last path element, or the one before it when the last is a
+// major version suffix like /v2. The .vN suffix of gopkg.in paths is kept, as
+// go install does.
+func getBinaryNameFromURI(uri string) string {
+	if strings.HasSuffix(uri, groupSuffix) {
+		return getGroupNameFromURI(uri)
+	}
+
+	dir, elem := path.Split(uri)
+	if dir != "" && isVersionElement(elem) {
+		_, elem = path.Split(path.Dir(uri))
+	}
+
+	return elem
+}
+
+// isVersionElement reports whether the path element is a major version suffix,
+// v2, v3, v10 and so on, but not v0, v1, v05 or v2ray, following go install.
+func isVersionElement(elem string) bool {
+	if len(elem) < 2 || elem[0] != 'v' || elem[1] == '0' || elem[1] == '1' && len(elem) == 2 {
+		return false
+	}
+
+	for i := 1; i < len(elem); i++ {
+		if elem[i] < '0' || '9' < elem[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ClaimFunc returns an error when the binary name must not be installed, as it
+// belongs to another package or to no package at all.
+type ClaimFunc func(name string) error
+
+// collectBinaries moves the binaries installed into the staging dir to the bin
+// dir once checked, removing the binaries of the previous install no longer
+// produced. Renames within the bin dir replace the binaries atomically.
+// A group claims the binaries it did not install before, so it does not take
+// over the binaries of other packages.
+func (p *Package) collectBinaries(staging string, claim ClaimFunc) error {
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return fmt.Errorf("failed to read staging dir: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+
+	if len(names) == 0 {
+		return fmt.Errorf("no binaries installed for %s", p.URI)
+	}
+
+	err = p.checkBinaries(staging, names)
+	if err != nil {
+		return err
+	}
+
+	if !p.IsGroup() {
+		return p.collectBinary(staging, names)
+	}
+
+	for _, name := range names {
+		if claim == nil || slices.Contains(p.Binaries, name) {
+			continue
+		}
+
+		err = claim(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		err = os.Rename(filepath.Join(staging, name), filepath.Join(p.BinDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to move binary %s to %s: %w", name, p.BinDir, err)
+		}
+	}
+
+	for _, name := range p.Binaries {
+		if slices.Contains(names, name) {
+			continue
+		}
+
+		err = os.Remove(filepath.Join(p.BinDir, name))
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove binary %s no longer installed: %w", name, err)
+		}
+
+		slog.Info("Removed binary no longer installed", "package", p.Name, "binary", name)
+	}
+
+	p.Binaries = names
+
+	return nil
+}
+
+// collectBinary moves the binary of a single package to the bin dir with the
+// tracked name, which may be a custom one. The name detected from the path is
+// corrected when go install produced a different one.
+func (p *Package) collectBinary(staging string, names []string) error {
+	if len(names) != 1 {
+		return fmt.Errorf("expected one binary for %s, go install produced %d", p.URI, len(names))
+	}
+
+	produced := names[0]
+	expected := getBinaryNameFromURI(p.URI)
+	if produced != expected {
+		slog.Warn(
+			"go install produced a different binary name",
+			"package", p.URI,
+			"expected", expected,
+			"name", produced,
+		)
+		if p.Name == expected {
+			p.Name = produced
+		}
+	}
+
+	err := os.Rename(filepath.Join(staging, produced), filepath.Join(p.BinDir, p.Name))
+	if err != nil {
+		return fmt.Errorf("failed to move binary %s to %s: %w", produced, p.BinDir, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package pkg
+
+import "testing"
+
+func TestGetBinaryNameFromURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want string
+	}{
+		{"github.com/tcondeixa/gomanager", "gomanager"},
+		{"github.com/goreleaser/goreleaser/v2", "goreleaser"},
+		{"github.com/golangci/golangci-lint/v2/cmd/golangci-lint", "golangci-lint"},
+		{"example.com/tool/v10", "tool"},
+		{"github.com/v2fly/v2ray-core/v5", "v2ray-core"},
+		{"example.com/v2ray", "v2ray"},
+		{"example.com/tools/vtest1", "vtest1"},
+		{"example.com/tool/v1", "v1"},
+		{"example.com/tool/v0", "v0"},
+		{"example.com/tool/v05", "v05"},
+		{"gopkg.in/yaml.v3", "yaml.v3"},
+		{"example.com/tool/cmd/...", "tool"},
+		{"example.com/tool/v2/cmd/...", "tool"},
+		{"example.com/tools/...", "tools"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			got := getBinaryNameFromURI(tt.uri)
+			if got != tt.want {
+				t.Errorf("getBinaryNameFromURI(%q) = %q, want %q", tt.uri, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsVersionElement(t *testing.T) {
+	tests := []struct {
+		elem string
+		want bool
+	}{
+		{"v2", true},
+		{"v10", true},
+		{"v1", false},
+		{"v0", false},
+		{"v05", false},
+		{"v", false},
+		{"v2ray", false},
+		{"vtest1", false},
+		{"yaml.v3", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.elem, func(t *testing.T) {
+			got := isVersionElement(tt.elem)
+			if got != tt.want {
+				t.Errorf("isVersionElement(%q) = %v, want %v", tt.elem, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CheckTimeout is how long the check of a binary may run.
+const CheckTimeout = 30 * time.Second
+
+// HasCheck reports whether the package has a check to run its binaries with.
+func (p *Package) HasCheck() bool {
+	return len(p.Check) > 0
+}
+
+// RunCheck runs the binary with the check arguments of the package, like
+// --version, failing when it exits with another code than the expected one or
+// does not finish in time.
+func (p *Package) RunCheck(binPath string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, binPath, p.Check...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	err := cmd.Run()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("check %s %s did not finish in %s", binPath, strings.Join(p.Check, " "), timeout)
+	}
+
+	code := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.ExitCode()
+	case err != nil:
+		return fmt.Errorf("check %s %s failed: %v", binPath, strings.Join(p.Check, " "), err)
+	}
+
+	if code != p.CheckExitCode {
+		return fmt.Errorf(
+			"check %s %s exited with %d, expected %d, output: %s",
+			binPath,
+			strings.Join(p.Check, " "),
+			code,
+			p.CheckExitCode,
+			output.String(),
+		)
+	}
+
+	return nil
+}
+
+// checkBinaries runs the check on the binaries built into the staging dir, so
+// a broken build never replaces the installed binaries.
+func (p *Package) checkBinaries(staging string, names []string) error {
+	if !p.HasCheck() {
+		return nil
+	}
+
+	for _, name := range names {
+		err := p.RunCheck(filepath.Join(staging, name), CheckTimeout)
+		if err != nil {
+			return fmt.Errorf("keeping the installed binary of %s: %w", p.Name, err)
+		}
+	}
+
+	return nil
+}
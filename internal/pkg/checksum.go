@@ -0,0 +1,48 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// checksumPrefix names the hash of the recorded checksums.
+const checksumPrefix = "sha256:"
+
+// FileChecksum returns the SHA-256 checksum of the file.
+func FileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	return checksumPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// RecordChecksums records the checksums of the installed binaries, to detect
+// binaries replaced or modified afterwards.
+func (p *Package) RecordChecksums(binDir string) error {
+	checksums := make(map[string]string, len(p.BinaryNames()))
+	for _, name := range p.BinaryNames() {
+		sum, err := FileChecksum(filepath.Join(binDir, name))
+		if err != nil {
+			return fmt.Errorf("failed to compute checksum of %s: %w", name, err)
+		}
+
+		checksums[name] = sum
+	}
+
+	p.Checksums = checksums
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package pkg
+
+import "strings"
+
+// groupSuffix is the go wildcard matching a package path and all the
+// packages below it.
+const groupSuffix = "/..."
+
+// IsGroup reports whether the package is a pattern installing every command
+// below a path, like example.com/mod/cmd/..., tracked as a single entry.
+func (p *Package) IsGroup() bool {
+	return strings.HasSuffix(p.URI, groupSuffix)
+}
+
+// BinaryNames returns the names of the binaries installed for the package.
+func (p *Package) BinaryNames() []string {
+	if p.IsGroup() {
+		return p.Binaries
+	}
+
+	return []string{p.Name}
+}
+
+// MatchesPath reports whether the binary built from the package path belongs
+// to the package.
+func (p *Package) MatchesPath(pkgPath string) bool {
+	if !p.IsGroup() {
+		return pkgPath == p.URI
+	}
+
+	prefix := strings.TrimSuffix(p.URI, groupSuffix)
+	return pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")
+}
+
+// getGroupNameFromURI names the group after the path before the wildcard,
+// skipping the cmd dir, so example.com/tool/cmd/... becomes tool.
+func getGroupNameFromURI(uri string) string {
+	uri = strings.TrimSuffix(uri, groupSuffix)
+	if strings.HasSuffix(uri, "/cmd") {
+		uri = strings.TrimSuffix(uri, "/cmd")
+	}
+
+	if uri == "" || strings.HasSuffix(uri, groupSuffix) {
+		return ""
+	}
+
+	return getBinaryNameFromURI(uri)
+}
@@ -0,0 +1,41 @@
+package pkg
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/tcondeixa/gomanager/internal/storage"
+	"golang.org/x/mod/semver"
+)
+
+// Migrations upgrade the packages saved by older versions of the storage file,
+// the first one upgrading from v1 to v2.
+var Migrations = []storage.Migration{
+	migrateInstalledVersion,
+}
+
+// migrateInstalledVersion fills the installed version, not recorded in v1,
+// for packages installed at an exact version.
+func migrateInstalledVersion(items map[string]json.RawMessage) error {
+	for key, raw := range items {
+		var item map[string]any
+		err := json.Unmarshal(raw, &item)
+		if err != nil {
+			return fmt.Errorf("failed to decode package %s: %w", key, err)
+		}
+
+		version, _ := item["version"].(string)
+		installed, _ := item["installed_version"].(string)
+		if installed == "" && semver.IsValid(version) {
+			item["installed_version"] = version
+		}
+
+		raw, err = json.Marshal(item)
+		if err != nil {
+			return fmt.Errorf("failed to encode package %s: %w", key, err)
+		}
+		items[key] = raw
+	}
+
+	return nil
+}
@@ -2,39 +2,171 @@ package pkg
 
 import (
 	"bytes"
+	"debug/buildinfo"
 	"fmt"
 	"log/slog"
+	"os"
 	"os/exec"
-	"regexp"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/tcondeixa/gomanager/internal/constraint"
 )
 
+const maxHistory = 10
+
+// DevelVersion is the version go records for a main module built from a dir,
+// tracked by the checksum of the binary instead.
+const DevelVersion = "(devel)"
+
+// Release is a version that was installed before the current one.
+type Release struct {
+	Version    string    `json:"version"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
 type Package struct {
-	Version   string    `json:"version"`
-	URI       string    `json:"uri"`
-	Name      string    `json:"name"`
-	UpdatedAt time.Time `json:"updated_at"`
+	Version          string            `json:"version"`
+	Pinned           bool              `json:"pinned,omitempty"`
+	InstalledVersion string            `json:"installed_version,omitempty"`
+	URI              string            `json:"uri"`
+	Module           string            `json:"module,omitempty"`
+	Name             string            `json:"name"`
+	Alias            string            `json:"alias,omitempty"`
+	Source           SourceType        `json:"source,omitempty"`
+	Repository       string            `json:"repository,omitempty"`
+	Dir              string            `json:"dir,omitempty"`
+	Binaries         []string          `json:"binaries,omitempty"`
+	BinDir           string            `json:"bin_dir,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	BuildFlags       []string          `json:"build_flags,omitempty"`
+	Env              []string          `json:"env,omitempty"`
+	Check            []string          `json:"check,omitempty"`
+	CheckExitCode    int               `json:"check_exit_code,omitempty"`
+	Sum              string            `json:"sum,omitempty"`
+	Checksums        map[string]string `json:"checksums,omitempty"`
+	UpdatedAt        time.Time         `json:"updated_at"`
+	History          []Release         `json:"history,omitempty"`
 }
 
+// New creates the package from a path@version argument of go install, where
+// the version can also be a constraint.
 func New(pkg string) (*Package, error) {
-	pkgList := strings.Split(pkg, "@")
-	name := getBinaryNameFromURI(pkgList[0])
+	uri, version, found := strings.Cut(pkg, "@")
+	if !found || version == "" {
+		return nil, fmt.Errorf("missing version in %s, use %s@latest or %s@<version>", pkg, uri, uri)
+	}
+
+	err := checkPackagePath(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	name := getBinaryNameFromURI(uri)
 	if name == "" {
-		return nil, fmt.Errorf("could not determine package name from URI: %s", pkgList[0])
+		return nil, fmt.Errorf("could not determine package name from URI: %s", uri)
 	}
 
 	return &Package{
-		Version:   pkgList[1],
-		URI:       pkgList[0],
+		Version:   version,
+		URI:       uri,
 		Name:      name,
 		UpdatedAt: time.Now(),
 	}, nil
 }
 
+// FromBuildInfo creates the package of an installed binary from its build
+// info, tracking the installed version.
+func FromBuildInfo(name string, info *debug.BuildInfo) Package {
+	return Package{
+		Version:          info.Main.Version,
+		InstalledVersion: info.Main.Version,
+		URI:              info.Path,
+		Module:           info.Main.Path,
+		Sum:              info.Main.Sum,
+		Name:             name,
+		UpdatedAt:        time.Now(),
+	}
+}
+
 func (p *Package) String() string {
+	installed := p.InstalledVersion
+	if installed == "" {
+		installed = "unknown"
+	}
+
+	tracking := p.Version
+	if p.Pinned {
+		tracking += " (pinned)"
+	}
+
+	if previous, ok := p.PreviousVersion(); ok {
+		installed += " (previous: " + previous.Version + ")"
+	}
+
+	location := ""
+	switch {
+	case p.BinDir != "" && p.IsGroup():
+		location = "Path: " + p.BinDir + "\n"
+	case p.BinDir != "":
+		location = "Path: " + filepath.Join(p.BinDir, p.Name) + "\n"
+	}
+
+	binaries := ""
+	if p.IsGroup() {
+		binaries = "Binaries: " + strings.Join(p.Binaries, ", ") + "\n"
+	}
+
+	variant := ""
+	if p.Alias != "" {
+		variant = "Variant of: " + p.Alias + "\n"
+	}
+
+	source := ""
+	switch p.SourceType() {
+	case SourceLocal:
+		source = "Source: local " + p.Dir + "\n"
+	case SourceGit:
+		source = "Source: git " + p.Repository
+		if p.Dir != "." {
+			source += " (" + p.Dir + ")"
+		}
+		source += "\n"
+	}
+
+	build := ""
+	if args := p.buildArgs(); len(args) > 0 || len(p.Env) > 0 {
+		build = "Build: " + strings.Join(append(slices.Clone(p.Env), args...), " ") + "\n"
+	}
+
+	check := ""
+	if p.HasCheck() {
+		check = "Check: " + strings.Join(p.Check, " ")
+		if p.CheckExitCode != 0 {
+			check += " (exit code " + strconv.Itoa(p.CheckExitCode) + ")"
+		}
+		check += "\n"
+	}
+
 	return "Name: " + p.Name + "\n" +
-		"URI: " + p.URI + "@" + p.Version + "\n" +
+		variant +
+		location +
+		binaries +
+		build +
+		check +
+		"URI: " + p.URI + "\n" +
+		source +
+		"Tracking: " + tracking + "\n" +
+		"Installed: " + installed + "\n" +
 		"Updated: " + p.UpdatedAt.String()
 }
 
@@ -46,41 +178,189 @@ func (p *Package) URIWithVersion() string {
 	return fmt.Sprintf("%s@%s", p.URI, p.Version)
 }
 
-func (p *Package) Install() (string, error) {
-	cmd := exec.Command("go", "install", p.URIWithVersion())
+// InstallCommand returns the go install command for the package at the given
+// version, which must be understood by go install, so constraints must be
+// resolved beforehand.
+// The binary is installed into the package bin dir when recorded, built with
+// the package build flags, tags and environment.
+// Packages from source are built in their dir, which is relative to the
+// checkout for git repositories, and the version is the ref to checkout.
+func (p *Package) InstallCommand(version string) *exec.Cmd {
+	args := append([]string{"install"}, p.buildArgs()...)
+	target := p.URI + "@" + version
+	if p.FromSource() {
+		target = "."
+	}
+
+	cmd := exec.Command("go", append(args, target)...)
+	cmd.Dir = p.Dir
+
+	if env := p.CommandEnv(); len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	return cmd
+}
+
+// CommandEnv returns the environment overrides of the install command.
+func (p *Package) CommandEnv() []string {
+	env := slices.Clone(p.Env)
+	if p.BinDir != "" {
+		env = append(env, "GOBIN="+p.BinDir)
+	}
+
+	return env
+}
+
+func (p *Package) buildArgs() []string {
+	args := slices.Clone(p.BuildFlags)
+	if len(p.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(p.Tags, ","))
+	}
+
+	return args
+}
+
+// InstallDir returns the dir where the package binary is installed, the
+// default dir for packages tracked before the dir was recorded.
+func (p *Package) InstallDir(defaultDir string) string {
+	if p.BinDir != "" {
+		return p.BinDir
+	}
+
+	return defaultDir
+}
+
+// Install builds the package at the version into the bin dir, with the claim
+// checking the binaries a group installs for the first time.
+func (p *Package) Install(version string, claim ClaimFunc) (string, error) {
+	cmd := p.InstallCommand(version)
+	if p.SourceType() == SourceGit {
+		checkout, err := gitCheckout(p.Repository, version)
+		if err != nil {
+			return "", err
+		}
+		defer os.RemoveAll(checkout)
+
+		cmd.Dir = filepath.Join(checkout, p.Dir)
+	}
+
+	// the binaries are only known from the files go install produces, so it
+	// installs into a staging dir in the bin dir, to move them from there.
+	// The bin dir is created like go install does for GOBIN.
+	err := os.MkdirAll(p.BinDir, 0o755)
+	if err != nil {
+		return "", fmt.Errorf("failed to create bin dir %s: %w", p.BinDir, err)
+	}
+
+	staging, err := os.MkdirTemp(p.BinDir, ".gomanager-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create staging dir: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, "GOBIN="+staging)
+
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
-	err := cmd.Run()
+	err = cmd.Run()
 	if err != nil {
 		return "", fmt.Errorf("failed to install package: %v, stderr: %s", err, stderr.String())
 	}
 
+	err = p.collectBinaries(staging, claim)
+	if err != nil {
+		return "", err
+	}
+
+	err = p.RecordChecksums(p.BinDir)
+	if err != nil {
+		return "", err
+	}
+
+	// go install reports progress, such as module downloads, to stderr
 	if stderr.Len() > 0 {
-		return "", fmt.Errorf("failed to install package, stderr: %s", stderr.String())
+		slog.Debug("go install stderr", "package", p.URI+"@"+version, "stderr", stderr.String())
 	}
 
 	return stdout.String(), nil
 }
 
+// ReadBuildInfo reads the build info embedded in the installed binary to find
+// out the module and the concrete module version that go install resolved.
+// A different version previously installed is kept in the history.
+func (p *Package) ReadBuildInfo(binDir string) error {
+	names := p.BinaryNames()
+	if len(names) == 0 {
+		return fmt.Errorf("no binaries recorded for %s", p.Name)
+	}
+
+	// the binaries of a group are all built from the same module
+	info, err := buildinfo.ReadFile(filepath.Join(binDir, names[0]))
+	if err != nil {
+		return fmt.Errorf("failed to read build info of %s: %w", p.Name, err)
+	}
+
+	version := info.Main.Version
+	if version == DevelVersion {
+		version, err = contentVersion(filepath.Join(binDir, names[0]))
+		if err != nil {
+			return err
+		}
+	}
+
+	p.Module = info.Main.Path
+	p.Sum = info.Main.Sum
+	if p.InstalledVersion != "" && p.InstalledVersion != version {
+		p.History = append(p.History, Release{Version: p.InstalledVersion, ReplacedAt: time.Now()})
+		if len(p.History) > maxHistory {
+			p.History = p.History[len(p.History)-maxHistory:]
+		}
+	}
+	p.InstalledVersion = version
+
+	return nil
+}
+
+// contentVersion names the version of a binary built without a module version,
+// like from a local dir, after its checksum, as go only records it as (devel).
+// Rebuilds with changes are then kept in the history to roll back to.
+func contentVersion(binPath string) (string, error) {
+	checksum, err := FileChecksum(binPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read checksum of %s: %w", binPath, err)
+	}
+
+	return "devel-" + strings.TrimPrefix(checksum, checksumPrefix)[:12], nil
+}
+
+// PreviousVersion returns the version installed before the current one.
+func (p *Package) PreviousVersion() (Release, bool) {
+	if len(p.History) == 0 {
+		return Release{}, false
+	}
+
+	return p.History[len(p.History)-1], true
+}
+
 func (p *Package) UpdateVersion(version string) {
 	p.Version = version
 	p.UpdatedAt = time.Now()
 }
 
-func getBinaryNameFromURI(uri string) string {
-	splitSlash := strings.Split(uri, "/")
-
-	// example.com/cmd/tool/v2 becomes tool
-	matched, err := regexp.MatchString(`v\d+`, splitSlash[len(splitSlash)-1])
-	if err != nil {
-		slog.Error("failed to match regex", "error", err)
-		return ""
+func checkVersion(version string) error {
+	if constraint.IsConstraint(version) {
+		_, err := constraint.Parse(version)
+		return err
 	}
 
-	if matched {
-		return splitSlash[len(splitSlash)-2]
+	if strings.ContainsAny(version, "@ \t\n") {
+		return fmt.Errorf("invalid version %q", version)
 	}
 
-	return splitSlash[len(splitSlash)-1]
+	return nil
 }
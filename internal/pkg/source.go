@@ -0,0 +1,162 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// SourceType is where the package is built from.
+type SourceType string
+
+const (
+	// SourceModule packages are fetched from the module proxy.
+	SourceModule SourceType = "module"
+	// SourceLocal packages are built from a local checkout.
+	SourceLocal SourceType = "local"
+	// SourceGit packages are built from a git repository at a ref.
+	SourceGit SourceType = "git"
+)
+
+// LocalVersion is the version tracked by packages built from a local checkout.
+const LocalVersion = "local"
+
+// DefaultRef is the ref built when none is given for a git repository.
+const DefaultRef = "HEAD"
+
+// NewLocal creates a package built from the main package in the local dir.
+func NewLocal(dir string) (*Package, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine absolute path of %s: %w", dir, err)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dir %s: %w", dir, err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a dir", dir)
+	}
+
+	uri, err := mainImportPath(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return newSourcePackage(uri, LocalVersion, SourceLocal, "", dir)
+}
+
+// NewGit creates a package built from the main package in the dir of the git
+// repository at the ref, which can be a branch, a tag or a commit.
+func NewGit(repository, ref, dir string) (*Package, error) {
+	if ref == "" {
+		ref = DefaultRef
+	}
+
+	checkout, err := gitCheckout(repository, ref)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(checkout)
+
+	dir = filepath.Clean(dir)
+	if filepath.IsAbs(dir) || strings.HasPrefix(dir, "..") {
+		return nil, fmt.Errorf("invalid dir %s: must be relative to the repository root", dir)
+	}
+
+	uri, err := mainImportPath(filepath.Join(checkout, dir))
+	if err != nil {
+		return nil, err
+	}
+
+	return newSourcePackage(uri, ref, SourceGit, repository, dir)
+}
+
+func newSourcePackage(uri, version string, source SourceType, repository, dir string) (*Package, error) {
+	name := getBinaryNameFromURI(uri)
+	if name == "" {
+		return nil, fmt.Errorf("could not determine package name from URI: %s", uri)
+	}
+
+	return &Package{
+		Version:    version,
+		URI:        uri,
+		Name:       name,
+		Source:     source,
+		Repository: repository,
+		Dir:        dir,
+		UpdatedAt:  time.Now(),
+	}, nil
+}
+
+// SourceType returns where the package is built from, the module proxy for
+// packages tracked before sources were recorded.
+func (p *Package) SourceType() SourceType {
+	if p.Source == "" {
+		return SourceModule
+	}
+
+	return p.Source
+}
+
+// FromSource reports whether the package is built from a checkout instead of
+// being fetched from the module proxy, so there are no versions to resolve.
+func (p *Package) FromSource() bool {
+	return p.SourceType() != SourceModule
+}
+
+// mainImportPath returns the import path of the package in the dir, which must
+// be a main package to be installed.
+func mainImportPath(dir string) (string, error) {
+	cmd := exec.Command("go", "list", "-f", "{{.ImportPath}} {{.Name}}", ".")
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("failed to find go package in %s: %v, stderr: %s", dir, err, stderr.String())
+	}
+
+	uri, name, _ := strings.Cut(strings.TrimSpace(stdout.String()), " ")
+	if name != "main" {
+		return "", fmt.Errorf("package %s in %s is not a main package", uri, dir)
+	}
+
+	return uri, nil
+}
+
+// gitCheckout fetches only the ref of the repository into a temporary dir,
+// which the caller must remove.
+func gitCheckout(repository, ref string) (string, error) {
+	dir, err := os.MkdirTemp("", "gomanager-git-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create checkout dir: %w", err)
+	}
+
+	commands := [][]string{
+		{"init", "--quiet"},
+		{"fetch", "--quiet", "--depth", "1", repository, ref},
+		{"checkout", "--quiet", "FETCH_HEAD"},
+	}
+	for _, args := range commands {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		err = cmd.Run()
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("failed to checkout %s at %s: %v, stderr: %s", repository, ref, err, stderr.String())
+		}
+	}
+
+	return dir, nil
+}
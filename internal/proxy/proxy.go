@@ -0,0 +1,175 @@
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/module"
+)
+
+const (
+	DefaultURL = "https://proxy.golang.org,direct"
+	timeout    = 30 * time.Second
+)
+
+var errNotFound = errors.New("not found")
+
+type endpoint struct {
+	url string
+	// fallThrough is true when the next proxy must be tried on any error,
+	// false when only a not found response allows trying the next one.
+	fallThrough bool
+}
+
+type Client struct {
+	endpoints []endpoint
+	http      *http.Client
+}
+
+type Info struct {
+	Version string    `json:"Version"`
+	Time    time.Time `json:"Time"`
+}
+
+// New creates a client for the GOPROXY list, following the go command rules:
+// proxies separated by "," are only skipped on not found responses,
+// proxies separated by "|" are skipped on any error.
+// The "direct" and "off" entries end the list, as there is no proxy to query.
+func New(goproxy string) (*Client, error) {
+	if strings.TrimSpace(goproxy) == "" {
+		goproxy = DefaultURL
+	}
+
+	var endpoints []endpoint
+	for goproxy != "" {
+		var entry string
+		fallThrough := false
+		i := strings.IndexAny(goproxy, ",|")
+		if i >= 0 {
+			entry = goproxy[:i]
+			fallThrough = goproxy[i] == '|'
+			goproxy = goproxy[i+1:]
+		} else {
+			entry = goproxy
+			goproxy = ""
+		}
+
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if entry == "direct" || entry == "off" {
+			break
+		}
+
+		endpoints = append(endpoints, endpoint{url: strings.TrimSuffix(entry, "/"), fallThrough: fallThrough})
+	}
+
+	if len(endpoints) == 0 {
+		return nil, fmt.Errorf("no module proxy available in GOPROXY")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.RegisterProtocol("file", http.NewFileTransport(http.Dir("/")))
+
+	return &Client{
+		endpoints: endpoints,
+		http:      &http.Client{Transport: transport, Timeout: timeout},
+	}, nil
+}
+
+// Latest returns the newest version of the module known by the proxy.
+func (c *Client) Latest(modulePath string) (Info, error) {
+	var info Info
+	body, err := c.get(modulePath, "@latest")
+	if err != nil {
+		return info, err
+	}
+
+	err = json.Unmarshal(body, &info)
+	if err != nil {
+		return info, fmt.Errorf("failed to decode latest version of %s: %w", modulePath, err)
+	}
+
+	return info, nil
+}
+
+// Versions returns the list of tagged versions of the module known by the proxy.
+func (c *Client) Versions(modulePath string) ([]string, error) {
+	body, err := c.get(modulePath, "@v/list")
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(body)), nil
+}
+
+// FindModule returns the module path that provides the package, trying the
+// longest prefix of the package path first, as the go command does.
+// A pattern like example.com/mod/cmd/... is looked up by the path before it.
+func (c *Client) FindModule(pkgPath string) (string, error) {
+	pkgPath = strings.TrimSuffix(pkgPath, "/...")
+	for candidate := pkgPath; candidate != "." && candidate != "/"; candidate = path.Dir(candidate) {
+		_, err := c.Latest(candidate)
+		if err == nil {
+			return candidate, nil
+		}
+
+		if !errors.Is(err, errNotFound) {
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("no module found providing package %s", pkgPath)
+}
+
+func (c *Client) get(modulePath, suffix string) ([]byte, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid module path %s: %w", modulePath, err)
+	}
+
+	var lastErr error
+	for _, e := range c.endpoints {
+		body, err := c.fetch(e.url + "/" + escaped + "/" + suffix)
+		if err == nil {
+			return body, nil
+		}
+
+		lastErr = err
+		if !e.fallThrough && !errors.Is(err, errNotFound) {
+			break
+		}
+	}
+
+	return nil, lastErr
+}
+
+func (c *Client) fetch(url string) ([]byte, error) {
+	resp, err := c.http.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query module proxy: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return nil, fmt.Errorf("%s: %w", url, errNotFound)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("module proxy returned %s for %s", resp.Status, url)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module proxy response: %w", err)
+	}
+
+	return body, nil
+}
@@ -0,0 +1,224 @@
+package proxy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newProxy serves the files of a module proxy, answering not found for the rest.
+func newProxy(t *testing.T, files map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, found := files[r.URL.Path]
+		if !found {
+			http.NotFound(w, r)
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+// newFailingProxy answers every request with an internal server error.
+func newFailingProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+var moduleFiles = map[string]string{
+	"/example.com/tool/@latest":          `{"Version":"v1.2.0","Time":"2024-01-02T03:04:05Z"}`,
+	"/example.com/tool/@v/list":          "v1.0.0\nv1.1.0\nv1.2.0\n",
+	"/example.com/tool/v2/@latest":       `{"Version":"v2.0.1","Time":"2024-02-02T03:04:05Z"}`,
+	"/github.com/!some!org/tool/@latest": `{"Version":"v0.3.0","Time":"2024-03-02T03:04:05Z"}`,
+	"/github.com/!some!org/tool/@v/list": "v0.1.0\nv0.3.0\n",
+	"/example.com/broken/@latest":        `not json`,
+}
+
+func TestLatest(t *testing.T) {
+	server := newProxy(t, moduleFiles)
+	client, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		module  string
+		want    string
+		wantErr bool
+	}{
+		{"example.com/tool", "v1.2.0", false},
+		{"example.com/tool/v2", "v2.0.1", false},
+		{"github.com/SomeOrg/tool", "v0.3.0", false},
+		{"example.com/missing", "", true},
+		{"example.com/broken", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			info, err := client.Latest(tt.module)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Latest(%q) error = %v, wantErr %v", tt.module, err, tt.wantErr)
+			}
+
+			if info.Version != tt.want {
+				t.Errorf("Latest(%q) = %q, want %q", tt.module, info.Version, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersions(t *testing.T) {
+	server := newProxy(t, moduleFiles)
+	client, err := New(server.URL + "/")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	versions, err := client.Versions("github.com/SomeOrg/tool")
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+
+	want := []string{"v0.1.0", "v0.3.0"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("Versions() = %v, want %v", versions, want)
+	}
+
+	_, err = client.Versions("example.com/missing")
+	if err == nil {
+		t.Error("Versions() of a missing module returned no error")
+	}
+}
+
+func TestFindModule(t *testing.T) {
+	server := newProxy(t, moduleFiles)
+	client, err := New(server.URL)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		pkgPath string
+		want    string
+		wantErr bool
+	}{
+		{"example.com/tool", "example.com/tool", false},
+		{"example.com/tool/cmd/tool", "example.com/tool", false},
+		{"example.com/tool/v2/cmd/tool", "example.com/tool/v2", false},
+		{"example.com/tool/cmd/...", "example.com/tool", false},
+		{"example.com/missing/cmd/tool", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pkgPath, func(t *testing.T) {
+			got, err := client.FindModule(tt.pkgPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindModule(%q) error = %v, wantErr %v", tt.pkgPath, err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("FindModule(%q) = %q, want %q", tt.pkgPath, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileProxy(t *testing.T) {
+	dir := t.TempDir()
+	err := os.MkdirAll(filepath.Join(dir, "example.com", "tool", "@v"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(filepath.Join(dir, "example.com", "tool", "@v", "list"), []byte("v1.0.0\nv1.1.0\n"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := New("file://" + filepath.ToSlash(dir))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	versions, err := client.Versions("example.com/tool")
+	if err != nil {
+		t.Fatalf("Versions() error = %v", err)
+	}
+
+	want := []string{"v1.0.0", "v1.1.0"}
+	if !slices.Equal(versions, want) {
+		t.Errorf("Versions() = %v, want %v", versions, want)
+	}
+
+	_, err = client.Latest("example.com/tool")
+	if err == nil {
+		t.Error("Latest() of a module without @latest returned no error")
+	}
+}
+
+func TestNewFallThrough(t *testing.T) {
+	good := newProxy(t, moduleFiles)
+	empty := newProxy(t, nil)
+	failing := newFailingProxy(t)
+
+	tests := []struct {
+		name    string
+		goproxy string
+		wantErr bool
+	}{
+		{"comma skips not found", empty.URL + "," + good.URL, false},
+		{"comma stops on errors", failing.URL + "," + good.URL, true},
+		{"pipe skips errors", failing.URL + "|" + good.URL, false},
+		{"pipe skips not found", empty.URL + "|" + good.URL, false},
+		{"direct ends the list", empty.URL + ",direct," + good.URL, true},
+		{"off ends the list", empty.URL + ",off," + good.URL, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := New(tt.goproxy)
+			if err != nil {
+				t.Fatalf("New(%q) error = %v", tt.goproxy, err)
+			}
+
+			info, err := client.Latest("example.com/tool")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Latest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && info.Version != "v1.2.0" {
+				t.Errorf("Latest() = %q, want v1.2.0", info.Version)
+			}
+		})
+	}
+}
+
+func TestNewWithoutProxy(t *testing.T) {
+	for _, goproxy := range []string{"direct", "off", "off,https://proxy.golang.org", " , "} {
+		_, err := New(goproxy)
+		if err == nil {
+			t.Errorf("New(%q) returned no error", goproxy)
+		}
+	}
+
+	client, err := New("")
+	if err != nil {
+		t.Fatalf("New(\"\") error = %v", err)
+	}
+
+	if len(client.endpoints) != 1 || client.endpoints[0].url != "https://proxy.golang.org" {
+		t.Errorf("New(\"\") endpoints = %v, want the default proxy", client.endpoints)
+	}
+}
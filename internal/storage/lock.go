@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	lockTimeout  = 10 * time.Second
+	lockInterval = 100 * time.Millisecond
+)
+
+var errLocked = errors.New("file is locked")
+
+// withLock runs fn holding both the in process mutex and an advisory lock on
+// the storage lock file, so concurrent gomanager processes do not overwrite
+// each other's changes.
+func (s *Provider[T]) withLock(fn func() error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.filePath+".lock", os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open storage lock file: %w", err)
+	}
+	defer file.Close()
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err = lockFile(file)
+		if err == nil {
+			break
+		}
+
+		if !errors.Is(err, errLocked) {
+			return fmt.Errorf("failed to lock storage file: %w", err)
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf(
+				"storage %s is locked by another gomanager process%s, gave up after %s",
+				s.filePath,
+				lockOwner(file),
+				lockTimeout,
+			)
+		}
+
+		time.Sleep(lockInterval)
+	}
+	defer func() {
+		_ = file.Truncate(0)
+		_ = unlockFile(file)
+	}()
+
+	err = file.Truncate(0)
+	if err == nil {
+		_, err = file.WriteAt([]byte(strconv.Itoa(os.Getpid())), 0)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write storage lock file: %w", err)
+	}
+
+	return fn()
+}
+
+func lockOwner(file *os.File) string {
+	buf := make([]byte, 32)
+	n, _ := file.ReadAt(buf, 0)
+	pid := strings.TrimSpace(string(buf[:n]))
+	if pid == "" {
+		return ""
+	}
+
+	return " (pid " + pid + ")"
+}
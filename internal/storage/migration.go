@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errNewerVersion = errors.New("storage file version not supported")
+
+// Migration upgrades the items of a storage file to the next version,
+// changing the encoded items in place.
+type Migration func(items map[string]json.RawMessage) error
+
+type rawFile struct {
+	Version   string                     `json:"version"`
+	UpdatedAt time.Time                  `json:"updated_at"`
+	Binaries  map[string]json.RawMessage `json:"binaries"`
+}
+
+func formatVersion(version int) string {
+	return "v" + strconv.Itoa(version)
+}
+
+func parseVersion(version string) (int, error) {
+	// files written before versioning was enforced may not have one
+	if version == "" {
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimPrefix(version, "v"))
+	if err != nil || n < 1 || !strings.HasPrefix(version, "v") {
+		return 0, fmt.Errorf("invalid storage file version %q", version)
+	}
+
+	return n, nil
+}
+
+// version is the current version of the storage file, the first version plus
+// one for each registered migration.
+func (s *Provider[T]) version() int {
+	return len(s.migrations) + 1
+}
+
+// migrate validates the version of the file content and runs the migrations
+// needed to upgrade it to the current version. The storage file is backed up
+// before being migrated and the migrated content is saved.
+func (s *Provider[T]) migrate(file string, data []byte) ([]byte, error) {
+	var raw rawFile
+	err := json.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal storage file: %w", err)
+	}
+
+	from, err := parseVersion(raw.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	current := s.version()
+	if from > current {
+		return nil, fmt.Errorf(
+			"%w: %s has version %s, newer than %s supported by this gomanager, please upgrade gomanager",
+			errNewerVersion,
+			file,
+			formatVersion(from),
+			formatVersion(current),
+		)
+	}
+
+	if from == current {
+		return data, nil
+	}
+
+	persist := file == s.filePath && s.report == nil
+	if file == s.filePath && s.report != nil {
+		s.report("migrate storage file " + file + " from " + formatVersion(from) + " to " + formatVersion(current))
+	}
+
+	if persist {
+		backup := file + "." + formatVersion(from) + ".bak"
+		err = writeFileAtomic(backup, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to backup storage file before migration: %w", err)
+		}
+		slog.Info("Backed up storage file before migration", "path", backup)
+	}
+
+	if raw.Binaries == nil {
+		raw.Binaries = map[string]json.RawMessage{}
+	}
+
+	for version := from; version < current; version++ {
+		err = s.migrations[version-1](raw.Binaries)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to migrate storage file from %s to %s: %w",
+				formatVersion(version),
+				formatVersion(version+1),
+				err,
+			)
+		}
+	}
+
+	raw.Version = formatVersion(current)
+	migrated, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode migrated storage file: %w", err)
+	}
+
+	if persist {
+		err = writeFileAtomic(file, migrated)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write migrated storage file: %w", err)
+		}
+	}
+
+	slog.Info("Migrated storage file", "path", file, "from", formatVersion(from), "to", formatVersion(current))
+
+	return migrated, nil
+}
@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testItem struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// renameField is a migration from v1 to v2, renaming the tag field to version.
+func renameField(items map[string]json.RawMessage) error {
+	for key, raw := range items {
+		var item map[string]any
+		err := json.Unmarshal(raw, &item)
+		if err != nil {
+			return err
+		}
+
+		item["version"] = item["tag"]
+		delete(item, "tag")
+		items[key], err = json.Marshal(item)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeFile(t *testing.T, file, content string) {
+	t.Helper()
+	err := os.WriteFile(file, []byte(content), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	v1 := `{"version": "v1", "binaries": {"tool": {"name": "tool", "tag": "v1.2.3"}}}`
+	writeFile(t, file, v1)
+
+	s := New[testItem](file, renameField)
+	err := s.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	item, found := s.GetItem("tool")
+	if !found || item.Version != "v1.2.3" {
+		t.Errorf("GetItem() = %+v, %v, want the migrated version v1.2.3", item, found)
+	}
+
+	backup, err := os.ReadFile(file + ".v1.bak")
+	if err != nil {
+		t.Fatalf("failed to read backup of the v1 file: %v", err)
+	}
+
+	if string(backup) != v1 {
+		t.Errorf("backup = %s, want the v1 file %s", backup, v1)
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var raw rawFile
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		t.Fatalf("failed to decode migrated file: %v", err)
+	}
+
+	if raw.Version != "v2" {
+		t.Errorf("migrated file version = %q, want v2", raw.Version)
+	}
+}
+
+func TestMigrateWithoutVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	writeFile(t, file, `{"binaries": {"tool": {"name": "tool", "tag": "v1.0.0"}}}`)
+
+	s := New[testItem](file, renameField)
+	err := s.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	item, _ := s.GetItem("tool")
+	if item.Version != "v1.0.0" {
+		t.Errorf("GetItem() = %+v, want the file without version migrated as v1", item)
+	}
+}
+
+func TestMigrateNewerVersion(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	writeFile(t, file, `{"version": "v3", "binaries": {}}`)
+	writeFile(t, file+".bak", `{"version": "v2", "binaries": {}}`)
+
+	s := New[testItem](file, renameField)
+	err := s.Start()
+	if !errors.Is(err, errNewerVersion) {
+		t.Fatalf("Start() error = %v, want %v", err, errNewerVersion)
+	}
+
+	_, err = os.Stat(file + ".v3.bak")
+	if !os.IsNotExist(err) {
+		t.Errorf("a newer file must not be backed up, stat error = %v", err)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+		wantErr bool
+	}{
+		{"", 1, false},
+		{"v1", 1, false},
+		{"v12", 12, false},
+		{"2", 0, true},
+		{"v0", 0, true},
+		{"vx", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			got, err := parseVersion(tt.version)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseVersion(%q) = %d, %v, want %d, wantErr %v", tt.version, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,20 +2,23 @@ package storage
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"maps"
 	"os"
+	"path/filepath"
+	"sync"
 	"time"
 )
 
-const version = "v1"
-
 type File[T any] struct {
 	Version   string       `json:"version"`
 	UpdatedAt time.Time    `json:"updated_at"`
 	Binaries  map[string]T `json:"binaries"`
 }
 
-func NewFile[T any]() File[T] {
+func NewFile[T any](version string) File[T] {
 	return File[T]{
 		UpdatedAt: time.Now(),
 		Version:   version,
@@ -24,51 +27,93 @@ func NewFile[T any]() File[T] {
 }
 
 type Provider[T any] struct {
+	mu         sync.Mutex
 	filePath   string
 	fileFormat File[T]
+	migrations []Migration
+	// report is set in dry run mode, receiving the changes not written
+	report func(change string)
 }
 
-func New[T any](filePath string) *Provider[T] {
-	return &Provider[T]{
+// New creates the storage provider, upgrading files written by older versions
+// with the migrations, where the first one upgrades from v1 to v2.
+func New[T any](filePath string, migrations ...Migration) *Provider[T] {
+	s := &Provider[T]{
 		filePath:   filePath,
-		fileFormat: NewFile[T](),
+		migrations: migrations,
 	}
+	s.fileFormat = NewFile[T](formatVersion(s.version()))
+
+	return s
+}
+
+// DryRun makes the provider keep the changes in memory, reporting the changes
+// that would be written to the storage file instead of writing them.
+func (s *Provider[T]) DryRun(report func(change string)) {
+	s.report = report
 }
 
 func (s *Provider[T]) Start() error {
-	err := s.ensureFile()
-	if err != nil {
-		return fmt.Errorf("failed to ensure storage file: %w", err)
-	}
+	return s.withLock(func() error {
+		if s.report != nil {
+			_, err := os.Stat(s.filePath)
+			if os.IsNotExist(err) {
+				s.report("create storage file " + s.filePath)
+				return nil
+			}
+		}
 
-	err = s.loadFile(s.filePath)
-	if err != nil {
-		return fmt.Errorf("failed to load storage file: %w", err)
-	}
+		err := s.ensureFile()
+		if err != nil {
+			return fmt.Errorf("failed to ensure storage file: %w", err)
+		}
 
-	return nil
+		return s.load()
+	})
 }
 
 func (s *Provider[T]) Import(file string) error {
-	err := s.loadFile(file)
-	if err != nil {
-		return fmt.Errorf("failed to import storage file: %w", err)
-	}
+	return s.withLock(func() error {
+		err := s.loadFile(file)
+		if err != nil {
+			return fmt.Errorf("failed to import storage file: %w", err)
+		}
+
+		if s.report != nil {
+			s.report("replace storage file " + s.filePath + " with " + file)
+			return nil
+		}
 
-	return s.saveFile(s.filePath)
+		return s.saveFile(s.filePath)
+	})
 }
 
 func (s *Provider[T]) Export(file string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	return s.saveFile(file)
 }
 
+// saveFile writes the file atomically, keeping the last good version of the
+// storage file as a backup.
 func (s *Provider[T]) saveFile(file string) error {
 	bytes, err := json.MarshalIndent(s.fileFormat, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to encode items to json: %w", err)
 	}
 
-	err = os.WriteFile(file, bytes, 0o644)
+	if file == s.filePath {
+		current, err := os.ReadFile(file)
+		if err == nil && json.Valid(current) {
+			err = writeFileAtomic(s.backupPath(), current)
+			if err != nil {
+				return fmt.Errorf("failed to write storage backup file: %w", err)
+			}
+		}
+	}
+
+	err = writeFileAtomic(file, bytes)
 	if err != nil {
 		return fmt.Errorf("failed to write items to file: %w", err)
 	}
@@ -76,6 +121,56 @@ func (s *Provider[T]) saveFile(file string) error {
 	return nil
 }
 
+func (s *Provider[T]) backupPath() string {
+	return s.filePath + ".bak"
+}
+
+// writeFileAtomic writes to a temporary file in the same directory, which is
+// synced and renamed into place, so readers never see a partial file.
+func writeFileAtomic(file string, data []byte) error {
+	dir := filepath.Dir(file)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(file)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if err != nil {
+		tmp.Close()
+		return err
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Chmod(tmp.Name(), 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tmp.Name(), file)
+	if err != nil {
+		return err
+	}
+
+	// sync the directory so the rename itself survives a crash
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	_ = d.Sync()
+
+	return nil
+}
+
 func (s *Provider[T]) ensureFile() error {
 	_, err := os.Stat(s.filePath)
 	if err == nil {
@@ -95,33 +190,89 @@ func (s *Provider[T]) loadFile(file string) error {
 	if err != nil {
 		return fmt.Errorf("failed to read storage file: %w", err)
 	}
-	err = json.Unmarshal(bytes, &s.fileFormat)
+
+	bytes, err = s.migrate(file, bytes)
+	if err != nil {
+		return err
+	}
+
+	fileFormat := NewFile[T](formatVersion(s.version()))
+	err = json.Unmarshal(bytes, &fileFormat)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal storage file: %w", err)
 	}
 
+	if fileFormat.Binaries == nil {
+		fileFormat.Binaries = map[string]T{}
+	}
+	s.fileFormat = fileFormat
+
 	return nil
 }
 
-func (s *Provider[T]) SaveItem(key string, item T) error {
-	s.fileFormat.Binaries[key] = item
-	s.fileFormat.UpdatedAt = time.Now()
+// load reads the storage file, falling back to the backup file when the
+// storage file can not be loaded, for example after an interrupted write.
+func (s *Provider[T]) load() error {
+	err := s.loadFile(s.filePath)
+	if err == nil || errors.Is(err, errNewerVersion) {
+		return err
+	}
+
+	backupErr := s.loadFile(s.backupPath())
+	if backupErr != nil {
+		return fmt.Errorf("failed to load storage file: %w", err)
+	}
 
-	return s.saveFile(s.filePath)
+	slog.Warn("failed to load storage file, using backup", "error", err, "path", s.backupPath())
+
+	return nil
 }
 
-func (s *Provider[T]) DeleteItem(key string) error {
-	delete(s.fileFormat.Binaries, key)
-	s.fileFormat.UpdatedAt = time.Now()
+// update reloads the storage file before applying the change, so changes
+// saved by other processes since it was loaded are kept.
+func (s *Provider[T]) update(description string, change func()) error {
+	return s.withLock(func() error {
+		if s.report != nil {
+			change()
+			s.report(description + " in storage file " + s.filePath)
+			return nil
+		}
+
+		err := s.load()
+		if err != nil {
+			return err
+		}
 
-	return s.saveFile(s.filePath)
+		change()
+		s.fileFormat.UpdatedAt = time.Now()
+
+		return s.saveFile(s.filePath)
+	})
+}
+
+func (s *Provider[T]) SaveItem(key string, item T) error {
+	return s.update("save entry "+key, func() {
+		s.fileFormat.Binaries[key] = item
+	})
+}
+
+func (s *Provider[T]) DeleteItem(key string) error {
+	return s.update("delete entry "+key, func() {
+		delete(s.fileFormat.Binaries, key)
+	})
 }
 
 func (s *Provider[T]) GetItem(key string) (T, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	val, ok := s.fileFormat.Binaries[key]
 	return val, ok
 }
 
 func (s *Provider[T]) GetAllItems() map[string]T {
-	return s.fileFormat.Binaries
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return maps.Clone(s.fileFormat.Binaries)
 }
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadFallsBackToBackup(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	writeFile(t, file, `{"version": "v1", "binaries": {"tool": {"name": "to`)
+	writeFile(t, file+".bak", `{"version": "v1", "binaries": {"tool": {"name": "tool", "version": "v1.0.0"}}}`)
+
+	s := New[testItem](file)
+	err := s.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	item, found := s.GetItem("tool")
+	if !found || item.Version != "v1.0.0" {
+		t.Errorf("GetItem() = %+v, %v, want the item of the backup", item, found)
+	}
+}
+
+func TestLoadWithoutBackup(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	writeFile(t, file, `not json`)
+
+	s := New[testItem](file)
+	err := s.Start()
+	if err == nil {
+		t.Fatal("Start() of a corrupt file without backup returned no error")
+	}
+}
+
+func TestSaveKeepsBackup(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "storage.json")
+	s := New[testItem](file)
+	err := s.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	err = s.SaveItem("tool", testItem{Name: "tool", Version: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("SaveItem() error = %v", err)
+	}
+
+	err = s.SaveItem("tool", testItem{Name: "tool", Version: "v1.1.0"})
+	if err != nil {
+		t.Fatalf("SaveItem() error = %v", err)
+	}
+
+	backup := New[testItem](file + ".bak")
+	err = backup.Start()
+	if err != nil {
+		t.Fatalf("failed to load backup: %v", err)
+	}
+
+	item, _ := backup.GetItem("tool")
+	if item.Version != "v1.0.0" {
+		t.Errorf("backup item = %+v, want the version saved before the last save", item)
+	}
+}